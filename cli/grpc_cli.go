@@ -2,10 +2,15 @@ package cli
 
 import (
 	"github.com/ringbrew/gsv/discovery"
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/metrics"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+var cliLog = logger.Named("cli")
+
 type LoadBalancePolicy int
 
 const (
@@ -14,7 +19,10 @@ const (
 )
 
 type Option struct {
+	// Secure dials with TLS by Credentials, or by TLS when Credentials is nil.
 	Secure             bool
+	TLS                TLSOption
+	Credentials        CredentialProvider
 	LoadBalancePolicy  LoadBalancePolicy
 	StreamInterceptors []grpc.StreamClientInterceptor
 	UnaryInterceptors  []grpc.UnaryClientInterceptor
@@ -25,10 +33,12 @@ func Classic() Option {
 		Secure: false,
 		StreamInterceptors: []grpc.StreamClientInterceptor{
 			TraceStreamInterceptor(),
+			metrics.StreamClientInterceptor(),
 		},
 		UnaryInterceptors: []grpc.UnaryClientInterceptor{
 			TraceUnaryInterceptor(),
 			LogUnaryInterceptor(),
+			metrics.UnaryClientInterceptor(),
 		},
 	}
 }
@@ -56,6 +66,18 @@ func NewClient(target string, opts ...Option) (Client, error) {
 
 	if !opt.Secure {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		var creds credentials.TransportCredentials
+		var err error
+		if opt.Credentials != nil {
+			creds, err = opt.Credentials(target)
+		} else {
+			creds, err = NewTLSCredentials(opt.TLS)
+		}
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
 	}
 
 	if len(opt.UnaryInterceptors) > 0 {
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ringbrew/gsv/logger"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// countedValue counts the times it is marshalled for the log.
+type countedValue struct {
+	count *int
+}
+
+func (v countedValue) MarshalJSON() ([]byte, error) {
+	*v.count++
+	return []byte(`{}`), nil
+}
+
+func TestLogUnaryInterceptorLazyRedact(t *testing.T) {
+	rec := logger.Record(t)
+
+	cc, err := grpc.NewClient("passthrough:///test", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer cc.Close()
+
+	count := 0
+	req, reply := countedValue{count: &count}, countedValue{count: &count}
+	invoke := func(failed error) error {
+		return LogUnaryInterceptor()(context.Background(), "/gsv.test.Pay/Create", req, reply, cc,
+			func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				return failed
+			})
+	}
+
+	// the values are not marshalled when the entries are not logged.
+	logger.SetLevel(logger.LevelOff)
+	require.NoError(t, invoke(nil))
+	require.Error(t, invoke(errors.New("failed")))
+	require.Equal(t, 0, count)
+	require.Empty(t, rec.Entries())
+
+	logger.SetLevel(logger.LevelInfo)
+	require.NoError(t, invoke(nil))
+	require.Equal(t, 2, count)
+	require.Equal(t, 1, rec.Entries().Message("rpc call success").Len())
+}
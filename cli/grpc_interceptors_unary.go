@@ -29,21 +29,32 @@ func LogUnaryInterceptor() grpc.UnaryClientInterceptor {
 	) error {
 		start := time.Now()
 		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		redact := logger.Redaction()
 		if err != nil {
-			logger.Error(logger.NewEntry(ctx).WithMessage(fmt.Sprintf("call service[%s]-method[%s] req[%v], error[%s]", cc.Target(), method, req, err.Error())))
+			entry := logger.NewEntry(ctx)
+			// the request is redacted only when the entry is logged, which is costly for the proto messages.
+			if cliLog.Enabled(logger.LevelError, entry) {
+				cliLog.Error(entry.WithMessage(fmt.Sprintf("call service[%s]-method[%s] req[%s], error[%s]", cc.Target(), method, redact.Value(req), err.Error())))
+			}
 		} else {
 			elapsed := time.Since(start)
 			entry := logger.NewEntry(ctx)
 			entry.WithExtra("service", cc.Target())
 			entry.WithExtra("duration", elapsed.String())
 			entry.WithExtra("method", method)
-			entry.WithExtra("req", req)
-			entry.WithExtra("resp", reply)
 
+			level := logger.LevelInfo
 			if elapsed > slowThreshold {
-				logger.Warn(entry.WithMessage("rpc call slow"))
-			} else {
-				logger.Info(entry.WithMessage("rpc call success"))
+				level = logger.LevelWarn
+			}
+			if cliLog.Enabled(level, entry) {
+				entry.WithExtra("req", redact.Value(req))
+				entry.WithExtra("resp", redact.Value(reply))
+				if level == logger.LevelWarn {
+					cliLog.Warn(entry.WithMessage("rpc call slow"))
+				} else {
+					cliLog.Info(entry.WithMessage("rpc call success"))
+				}
 			}
 		}
 		return err
@@ -86,6 +97,7 @@ func TraceUnaryInterceptor() grpc.UnaryClientInterceptor {
 
 		sc := span.SpanContext()
 		rpcCtx := tracex.NewServiceContext(sc.TraceID(), sc.SpanID())
+		tracex.CopyBaggage(ctx, rpcCtx)
 		ctx = service.NewContext(ctx, rpcCtx)
 
 		err := invoker(ctx, method, req, reply, cc, callOpts...)
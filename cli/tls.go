@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/ringbrew/gsv/internal/tlsx"
+	"google.golang.org/grpc/credentials"
+	"net"
+	"time"
+)
+
+// TLSOption configures the client TLS used when Option.Secure is true.
+type TLSOption struct {
+	// CAFile is the pem bundle to verify the servers by, the system roots are used when empty.
+	CAFile string
+	// CertFile and KeyFile are the client certificate presented for mTLS.
+	CertFile string
+	KeyFile  string
+	// ServerName is the name, or the ip, verified in the server certificate, the host of the target by default.
+	ServerName string
+	// MinVersion is tls.VersionTLS12 by default.
+	MinVersion uint16
+	// ReloadInterval is how often the files are checked for changes, 1m by default, negative disables reloading.
+	ReloadInterval time.Duration
+}
+
+// CredentialProvider returns the transport credentials to dial target with, it takes precedence over TLSOption.
+type CredentialProvider func(target string) (credentials.TransportCredentials, error)
+
+// NewTLSCredentials returns the transport credentials of opt, the files are reloaded once modified.
+func NewTLSCredentials(opt TLSOption) (credentials.TransportCredentials, error) {
+	r, err := newTLSReloader(opt)
+	if err != nil {
+		return nil, err
+	}
+	return &tlsCredentials{r: r, serverName: opt.ServerName}, nil
+}
+
+// tlsCredentials verifies the server by the roots and presents the certificate loaded currently, a tls.Config
+// is built for every handshake as they may be reloaded.
+type tlsCredentials struct {
+	r          *tlsReloader
+	serverName string
+}
+
+func (c *tlsCredentials) creds() credentials.TransportCredentials {
+	return credentials.NewTLS(c.r.config(c.serverName))
+}
+
+// ClientHandshake verifies the server certificate for serverName, the host of authority by default.
+func (c *tlsCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.creds().ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *tlsCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("tls credentials of cli do not support the server handshake")
+}
+
+func (c *tlsCredentials) Info() credentials.ProtocolInfo {
+	return c.creds().Info()
+}
+
+func (c *tlsCredentials) Clone() credentials.TransportCredentials {
+	return &tlsCredentials{r: c.r, serverName: c.serverName}
+}
+
+func (c *tlsCredentials) OverrideServerName(serverName string) error {
+	c.serverName = serverName
+	return nil
+}
+
+// tlsFiles are the client certificate and the roots loaded from disk.
+type tlsFiles struct {
+	cert  *tls.Certificate
+	roots *x509.CertPool
+}
+
+type tlsReloader struct {
+	*tlsx.Reloader[tlsFiles]
+	opt TLSOption
+}
+
+func newTLSReloader(opt TLSOption) (*tlsReloader, error) {
+	if (opt.CertFile == "") != (opt.KeyFile == "") {
+		return nil, errors.New("tls cert file and key file should be set together")
+	}
+	if opt.MinVersion == 0 {
+		opt.MinVersion = tls.VersionTLS12
+	}
+
+	files := make([]string, 0, 3)
+	for _, v := range []string{opt.CAFile, opt.CertFile, opt.KeyFile} {
+		if v != "" {
+			files = append(files, v)
+		}
+	}
+
+	r, err := tlsx.NewReloader(files, opt.ReloadInterval, cliLog, func() (tlsFiles, error) {
+		var result tlsFiles
+		var err error
+		if opt.CertFile != "" {
+			if result.cert, err = tlsx.LoadKeyPair(opt.CertFile, opt.KeyFile); err != nil {
+				return tlsFiles{}, err
+			}
+		}
+		if opt.CAFile != "" {
+			if result.roots, err = tlsx.LoadCertPool(opt.CAFile); err != nil {
+				return tlsFiles{}, err
+			}
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tlsReloader{Reloader: r, opt: opt}, nil
+}
+
+// config returns the config of the files loaded currently, the server is verified by the roots for serverName.
+func (r *tlsReloader) config(serverName string) *tls.Config {
+	files := r.Current()
+	return &tls.Config{
+		ServerName: serverName,
+		MinVersion: r.opt.MinVersion,
+		RootCAs:    files.roots,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if files.cert != nil {
+				return files.cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ringbrew/gsv/internal/tlstest"
+	"github.com/ringbrew/gsv/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestClientMTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCert(t, "ca", nil)
+	ca.Write(t, filepath.Join(dir, "ca.pem"), "")
+	// the server certificate has no ip, so dialing 127.0.0.1 needs the ServerName.
+	tlstest.NewHostCert(t, "server", []string{tlstest.DNSName}, ca, x509.ExtKeyUsageServerAuth).Write(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"))
+	tlstest.NewCert(t, "client", ca, x509.ExtKeyUsageClientAuth).Write(t, filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key"))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+
+	s := server.NewServer(server.GRPC, &server.Option{
+		Port:     port,
+		CertFile: filepath.Join(dir, "server.pem"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+		TLS:      server.TLSOption{ClientCAFile: filepath.Join(dir, "ca.pem")},
+		Health:   server.HealthOption{Enable: true},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- s.Run(ctx)
+	}()
+	// the server stops logging before the next test records the logs.
+	defer func() {
+		cancel()
+		select {
+		case err := <-result:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("server run timeout")
+		}
+	}()
+	select {
+	case <-s.Ready():
+	case err := <-result:
+		t.Fatal(err)
+	}
+
+	check := func(opt TLSOption) error {
+		c, err := NewClient(fmt.Sprintf("127.0.0.1:%d", port), Option{Secure: true, TLS: opt})
+		if err != nil {
+			return err
+		}
+		defer c.Conn().Close()
+		_, err = grpc_health_v1.NewHealthClient(c.Conn()).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		return err
+	}
+
+	require.NoError(t, check(TLSOption{
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		CertFile:   filepath.Join(dir, "client.pem"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		ServerName: tlstest.DNSName,
+	}))
+	require.Error(t, check(TLSOption{CAFile: filepath.Join(dir, "ca.pem"), ServerName: tlstest.DNSName}))
+	require.Error(t, check(TLSOption{
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		CertFile:   filepath.Join(dir, "client.pem"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		ServerName: "other.test",
+	}))
+	require.Error(t, check(TLSOption{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client.key"),
+	}))
+}
@@ -48,6 +48,24 @@ func (l YmlLoader) Load(result interface{}) error {
 	return nil
 }
 
+// LoadDocument loads result and returns the document of the file, which tells the keys set by the file.
+func (l YmlLoader) LoadDocument(result interface{}) (map[string]interface{}, string, error) {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = yaml.Unmarshal(data, result); err != nil {
+		return nil, "", err
+	}
+
+	doc := make(map[string]interface{})
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return nil, "", err
+	}
+	return doc, tagYaml, nil
+}
+
 type JsonLoader struct {
 	path string
 }
@@ -64,3 +82,21 @@ func (l JsonLoader) Load(result interface{}) error {
 
 	return nil
 }
+
+// LoadDocument loads result and returns the document of the file, which tells the keys set by the file.
+func (l JsonLoader) LoadDocument(result interface{}) (map[string]interface{}, string, error) {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, "", err
+	}
+
+	doc := make(map[string]interface{})
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return nil, "", err
+	}
+	return doc, tagJson, nil
+}
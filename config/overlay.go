@@ -0,0 +1,440 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source is where the value of a field is supplied from, in the precedence of
+// SourceDefault < SourceFile < SourceEnv < SourceFlag.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+const (
+	tagEnv     = "env"
+	tagDefault = "default"
+	tagFlag    = "flag"
+	tagYaml    = "yaml"
+	tagJson    = "json"
+)
+
+// Report is the source of the fields supplied, by the path of the field like 'Server.Port'.
+type Report map[string]Source
+
+func (r Report) String() string {
+	paths := make([]string, 0, len(r))
+	for k := range r {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, v := range paths {
+		sb.WriteString(fmt.Sprintf("%s=%s\n", v, r[v]))
+	}
+	return sb.String()
+}
+
+type OverlayOption struct {
+	// Prefix is prepended to the env names, like 'GSV_' for `env:"PORT"` to be read from GSV_PORT.
+	Prefix string
+	// LookupEnv reads the env, os.LookupEnv by default.
+	LookupEnv func(key string) (string, bool)
+	// FlagSet overrides the fields of `flag:"name"` by the flags set on the command line, it should be parsed already.
+	FlagSet *flag.FlagSet
+}
+
+// OverlayLoader loads the config by the struct tags over the file of Loader:
+//
+//	type Config struct {
+//		Port  int      `yaml:"port" env:"PORT" default:"3000" flag:"port"`
+//		Hosts []string `yaml:"hosts" env:"HOSTS" default:"a,b"`
+//		DB    DBConfig `yaml:"db" env:"DB"`
+//	}
+//
+// The fields of the nested structs have the env names prefixed by the one of the struct field, like DB_DSN, and
+// the ones of the structs without the env tag are not read from env. The slices are read from the comma
+// separated values.
+type OverlayLoader struct {
+	file   Loader
+	opt    OverlayOption
+	report Report
+}
+
+// NewOverlayLoader returns the OverlayLoader over file, which can be nil for the config of env and defaults only.
+func NewOverlayLoader(file Loader, opt OverlayOption) *OverlayLoader {
+	if opt.LookupEnv == nil {
+		opt.LookupEnv = os.LookupEnv
+	}
+	return &OverlayLoader{
+		file: file,
+		opt:  opt,
+	}
+}
+
+func (l *OverlayLoader) Load(result interface{}) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config overlay: result should be a pointer to struct, got %T", result)
+	}
+
+	report := make(Report)
+
+	if l.file != nil {
+		doc, tag, err := loadDocument(l.file, result)
+		if err != nil {
+			return err
+		}
+		fileFields(rv.Elem().Type(), doc, tag, "", report)
+	}
+
+	if _, err := walkFields(rv.Elem(), "", l.opt.Prefix, true, func(f field) (bool, error) {
+		if f.env == "" {
+			return false, nil
+		}
+		v, ok := l.opt.LookupEnv(f.env)
+		if !ok {
+			return false, nil
+		}
+		if err := setString(f.v, v); err != nil {
+			return false, fmt.Errorf("config overlay: env %s of %s: %w", f.env, f.path, err)
+		}
+		report[f.path] = SourceEnv
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	if l.opt.FlagSet != nil {
+		set := make(map[string]*flag.Flag)
+		l.opt.FlagSet.Visit(func(fl *flag.Flag) {
+			set[fl.Name] = fl
+		})
+		if _, err := walkFields(rv.Elem(), "", "", false, func(f field) (bool, error) {
+			name, ok := f.sf.Tag.Lookup(tagFlag)
+			if !ok {
+				return false, nil
+			}
+			fl, ok := set[name]
+			if !ok {
+				return false, nil
+			}
+			if err := setString(f.v, fl.Value.String()); err != nil {
+				return false, fmt.Errorf("config overlay: flag %s of %s: %w", name, f.path, err)
+			}
+			report[f.path] = SourceFlag
+			return true, nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	// the defaults go last for the fields not supplied, so the nil sections are not allocated for them alone.
+	if _, err := walkFields(rv.Elem(), "", "", false, func(f field) (bool, error) {
+		v, ok := f.sf.Tag.Lookup(tagDefault)
+		if _, supplied := report[f.path]; !ok || supplied || f.detached {
+			return false, nil
+		}
+		if err := setString(f.v, v); err != nil {
+			return false, fmt.Errorf("config overlay: default of %s: %w", f.path, err)
+		}
+		report[f.path] = SourceDefault
+		return false, nil
+	}); err != nil {
+		return err
+	}
+
+	l.report = report
+	return nil
+}
+
+// Report returns the sources of the fields supplied by the last Load.
+func (l *OverlayLoader) Report() Report {
+	return l.report
+}
+
+type field struct {
+	sf   reflect.StructField
+	v    reflect.Value
+	path string
+	env  string
+	// detached is the field of a nil section, which is kept only when the field is set.
+	detached bool
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isLeaf reports whether the value of t is set from a string, other than a struct walked into.
+func isLeaf(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	return t.Kind() != reflect.Struct
+}
+
+// walkFields calls f with the leaf fields of the struct v and reports whether f sets any of them, the nested
+// structs are walked into. The nil pointers to them are allocated only when f reports their fields set, and the
+// env names are given to the fields when readEnv, except the ones of the structs without the env tag.
+func walkFields(v reflect.Value, path string, envPrefix string, readEnv bool, f func(f field) (bool, error)) (bool, error) {
+	return walkFieldsOf(v, path, envPrefix, readEnv, false, f)
+}
+
+func walkFieldsOf(v reflect.Value, path string, envPrefix string, readEnv bool, detached bool, f func(f field) (bool, error)) (bool, error) {
+	t := v.Type()
+	set := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		fpath := fieldPath(path, sf)
+		env := ""
+		if name, ok := sf.Tag.Lookup(tagEnv); ok && name != "-" && readEnv {
+			env = envPrefix + name
+		}
+
+		if isLeaf(sf.Type) {
+			ok, err := f(field{sf: sf, v: fv, path: fpath, env: env, detached: detached})
+			if err != nil {
+				return false, err
+			}
+			set = set || ok
+			continue
+		}
+
+		childPrefix := env + "_"
+
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			elem := reflect.New(sf.Type.Elem())
+			ok, err := walkFieldsOf(elem.Elem(), fpath, childPrefix, env != "", true, f)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				fv.Set(elem)
+				set = true
+			}
+			continue
+		}
+
+		if fv.Kind() == reflect.Ptr {
+			fv = fv.Elem()
+		}
+		ok, err := walkFieldsOf(fv, fpath, childPrefix, env != "", detached, f)
+		if err != nil {
+			return false, err
+		}
+		set = set || ok
+	}
+	return set, nil
+}
+
+// documentLoader is the Loader decoding the document of the file along with result, by the keys of tag, so
+// the file is read once to tell the fields it supplies. The loaders of NewLoader implement it.
+type documentLoader interface {
+	LoadDocument(result interface{}) (doc map[string]interface{}, tag string, err error)
+}
+
+// loadDocument loads result by file and returns the document decoded, the other Loaders load the file twice
+// for it, and their fields are matched by the yaml then the json tags.
+func loadDocument(file Loader, result interface{}) (map[string]interface{}, string, error) {
+	if dl, ok := file.(documentLoader); ok {
+		return dl.LoadDocument(result)
+	}
+	if err := file.Load(result); err != nil {
+		return nil, "", err
+	}
+	doc := make(map[string]interface{})
+	if err := file.Load(&doc); err != nil {
+		return nil, "", err
+	}
+	return doc, "", nil
+}
+
+// fileFields reports the leaf fields of t set by the keys of doc as SourceFile, the zero values included.
+func fileFields(t reflect.Type, doc map[string]interface{}, tag string, path string, report Report) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		key, inline := documentKey(sf, tag)
+		if key == "-" {
+			continue
+		}
+		fpath := fieldPath(path, sf)
+		if inline && !isLeaf(sf.Type) {
+			fileFields(sf.Type, doc, tag, fpath, report)
+			continue
+		}
+
+		v, ok := lookupKey(doc, sf, key, tag)
+		if !ok {
+			continue
+		}
+		if isLeaf(sf.Type) {
+			report[fpath] = SourceFile
+			continue
+		}
+		if child, ok := v.(map[string]interface{}); ok {
+			fileFields(sf.Type, child, tag, fpath, report)
+		}
+	}
+}
+
+// fieldPath returns the path of sf in the struct of path, the embedded structs and the ones inlined by yaml take
+// the path of the parent, so the fields are reported alike by every source.
+func fieldPath(path string, sf reflect.StructField) string {
+	if !isLeaf(sf.Type) {
+		_, opts, _ := strings.Cut(sf.Tag.Get(tagYaml), ",")
+		if sf.Anonymous || strings.Contains(","+opts+",", ",inline,") {
+			return path
+		}
+	}
+	if path == "" {
+		return sf.Name
+	}
+	return path + "." + sf.Name
+}
+
+// documentKey returns the key of sf in the document by tag, and whether its fields are inlined into the parent.
+// The yaml then the json tags are taken when tag is empty.
+func documentKey(sf reflect.StructField, tag string) (string, bool) {
+	tags := []string{tag}
+	if tag == "" {
+		tags = []string{tagYaml, tagJson}
+	}
+	for _, v := range tags {
+		tv, ok := sf.Tag.Lookup(v)
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(tv, ",")
+		if v == tagYaml {
+			return name, strings.Contains(","+opts+",", ",inline,")
+		}
+		return name, sf.Anonymous && name == ""
+	}
+	if tag == tagYaml {
+		return "", false
+	}
+	return "", sf.Anonymous
+}
+
+// lookupKey finds the key of sf in doc, the keys of yaml are matched exactly, and the others case-insensitively as
+// encoding/json does.
+func lookupKey(doc map[string]interface{}, sf reflect.StructField, key string, tag string) (interface{}, bool) {
+	if tag == tagYaml {
+		if key == "" {
+			key = strings.ToLower(sf.Name)
+		}
+		v, ok := doc[key]
+		return v, ok
+	}
+
+	if key == "" {
+		key = sf.Name
+	}
+	if v, ok := doc[key]; ok {
+		return v, true
+	}
+	for k, v := range doc {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setString parses s into v by its kind, the slices are parsed from the comma separated values.
+func setString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := setString(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		var parts []string
+		if s != "" {
+			parts = strings.Split(s, ",")
+		}
+		result := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setString(result.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(result)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type dbConfig struct {
+	DSN     string        `yaml:"dsn" env:"DSN" default:"localhost:3306"`
+	Timeout time.Duration `yaml:"timeout" env:"TIMEOUT" default:"3s"`
+}
+
+type testConfig struct {
+	Name  string    `yaml:"name" env:"NAME" default:"gsv"`
+	Port  int       `yaml:"port" env:"PORT" default:"3000" flag:"port"`
+	Debug bool      `yaml:"debug" env:"DEBUG"`
+	Hosts []string  `yaml:"hosts" env:"HOSTS" default:"a,b"`
+	DB    dbConfig  `yaml:"db" env:"DB"`
+	Cache *dbConfig `yaml:"cache"`
+}
+
+func TestOverlayLoader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("name: user\nport: 4000\ndebug: false\ndb:\n  dsn: db:3306\n"), 0644))
+
+	env := map[string]string{
+		"GSV_PORT":       "5000",
+		"GSV_HOSTS":      "h1, h2",
+		"GSV_DB_TIMEOUT": "1m",
+		// the fields of the sections without the env tag are not read from env.
+		"GSV_DSN": "dropped",
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("port", 0, "")
+	require.NoError(t, fs.Parse([]string{"-port", "6000"}))
+
+	l := NewOverlayLoader(NewLoader(LoaderTypeYml, path), OverlayOption{
+		Prefix: "GSV_",
+		LookupEnv: func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		},
+		FlagSet: fs,
+	})
+
+	var c testConfig
+	require.NoError(t, l.Load(&c))
+	require.Equal(t, "user", c.Name)
+	require.Equal(t, 6000, c.Port)
+	require.Equal(t, []string{"h1", "h2"}, c.Hosts)
+	require.Equal(t, "db:3306", c.DB.DSN)
+	require.Equal(t, time.Minute, c.DB.Timeout)
+	// the optional section is not allocated by its defaults alone.
+	require.Nil(t, c.Cache)
+	require.False(t, c.Debug)
+
+	require.Equal(t, Report{
+		"Name":       SourceFile,
+		"Port":       SourceFlag,
+		"Debug":      SourceFile,
+		"Hosts":      SourceEnv,
+		"DB.DSN":     SourceFile,
+		"DB.Timeout": SourceEnv,
+	}, l.Report())
+
+	// the section set by the file takes the defaults of the fields it misses.
+	require.NoError(t, os.WriteFile(path, []byte("cache:\n  timeout: 1s\n"), 0644))
+	c = testConfig{}
+	require.NoError(t, l.Load(&c))
+	require.Equal(t, "localhost:3306", c.Cache.DSN)
+	require.Equal(t, time.Second, c.Cache.Timeout)
+	require.Equal(t, SourceDefault, l.Report()["Cache.DSN"])
+	require.Equal(t, SourceFile, l.Report()["Cache.Timeout"])
+
+	env["GSV_DEBUG"] = "maybe"
+	require.Error(t, l.Load(&c))
+	require.Error(t, l.Load(c))
+}
+
+// mapLoader is a Loader without the document of the file.
+type mapLoader map[string]interface{}
+
+func (l mapLoader) Load(result interface{}) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func TestOverlayLoaderCustomLoader(t *testing.T) {
+	l := NewOverlayLoader(mapLoader{"Port": 0, "db": map[string]interface{}{"DSN": "db:3306"}}, OverlayOption{
+		LookupEnv: func(key string) (string, bool) { return "", false },
+	})
+
+	var c testConfig
+	require.NoError(t, l.Load(&c))
+	require.Equal(t, 0, c.Port)
+	require.Equal(t, Report{
+		"Name":       SourceDefault,
+		"Port":       SourceFile,
+		"Hosts":      SourceDefault,
+		"DB.DSN":     SourceFile,
+		"DB.Timeout": SourceDefault,
+	}, l.Report())
+}
+
+type CommonConfig struct {
+	Port int    `json:"port" yaml:"port" default:"3000"`
+	Mode string `json:"mode" yaml:"mode" default:"release"`
+}
+
+func TestOverlayLoaderEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"port":8080}`), 0644))
+	ymlPath := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(ymlPath, []byte("port: 8080\n"), 0644))
+
+	env := OverlayOption{LookupEnv: func(key string) (string, bool) { return "", false }}
+	report := Report{"Port": SourceFile, "Mode": SourceDefault}
+
+	var jc struct {
+		CommonConfig
+		Name string `json:"name"`
+	}
+	l := NewOverlayLoader(NewLoader(LoaderTypeJson, jsonPath), env)
+	require.NoError(t, l.Load(&jc))
+	require.Equal(t, 8080, jc.Port)
+	require.Equal(t, "release", jc.Mode)
+	require.Equal(t, report, l.Report())
+
+	var yc struct {
+		Common CommonConfig `yaml:",inline"`
+		Name   string       `yaml:"name"`
+	}
+	l = NewOverlayLoader(NewLoader(LoaderTypeYml, ymlPath), env)
+	require.NoError(t, l.Load(&yc))
+	require.Equal(t, 8080, yc.Common.Port)
+	require.Equal(t, report, l.Report())
+}
@@ -11,6 +11,8 @@ import (
 
 const SchemeName = "gsv"
 
+var discoveryLog = logger.Named("discovery")
+
 func Register(nd NodeDiscover, tag ...string) {
 	resolver.Register(NewResolverBuilder(nd, tag...))
 }
@@ -54,7 +56,7 @@ func (rb *ResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn,
 	go func() {
 		defer func() {
 			if p := recover(); p != nil {
-				logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("service[%s] checker panic:%v", target.URL.Path, p)))
+				discoveryLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("service[%s] checker panic:%v", target.URL.Path, p)))
 			}
 		}()
 		ticker := time.NewTicker(time.Minute)
@@ -71,7 +73,7 @@ func (rb *ResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn,
 	go func() {
 		defer func() {
 			if p := recover(); p != nil {
-				logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("service[%s] watch panic:%v", target.URL.Path, p)))
+				discoveryLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("service[%s] watch panic:%v", target.URL.Path, p)))
 			}
 		}()
 		r.watch()
@@ -103,19 +105,19 @@ func (r *gsvResolver) watch() {
 	for event := range r.eventChan {
 		switch event.Event {
 		case NodeEventAdd:
-			logger.Debug(logger.NewEntry().WithMessage(fmt.Sprintf("target[%s] receive add event: %v", r.target.URL.String(), event)))
+			discoveryLog.Debug(logger.NewEntry().WithMessage(fmt.Sprintf("target[%s] receive add event: %v", r.target.URL.String(), event)))
 			for _, node := range event.Node {
 				r.cache[node.Id] = node
 			}
 			updateState()
 		case NodeEventRemove:
-			logger.Debug(logger.NewEntry().WithMessage(fmt.Sprintf("target[%s] receive remove event: %v", r.target.URL.String(), event)))
+			discoveryLog.Debug(logger.NewEntry().WithMessage(fmt.Sprintf("target[%s] receive remove event: %v", r.target.URL.String(), event)))
 			for _, node := range event.Node {
 				delete(r.cache, node.Id)
 			}
 			updateState()
 		case NodeEventSync:
-			logger.Debug(logger.NewEntry().WithMessage(fmt.Sprintf("target[%s] receive sync event: %v", r.target.URL.String(), event)))
+			discoveryLog.Debug(logger.NewEntry().WithMessage(fmt.Sprintf("target[%s] receive sync event: %v", r.target.URL.String(), event)))
 			r.cache = make(map[string]*Node)
 			for _, node := range event.Node {
 				r.cache[node.Id] = node
@@ -0,0 +1,84 @@
+// Package tlstest mints the certificates of the TLS tests of server and cli.
+package tlstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// DNSName is the name the certificates are issued for, besides 127.0.0.1.
+const DNSName = "gsv.test"
+
+type Cert struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+	Der  []byte
+}
+
+// NewCert mints a certificate of DNSName and 127.0.0.1 signed by parent for usages, the CA one when parent is nil.
+func NewCert(t *testing.T, cn string, parent *Cert, usages ...x509.ExtKeyUsage) *Cert {
+	return NewHostCert(t, cn, []string{DNSName, "127.0.0.1"}, parent, usages...)
+}
+
+// NewHostCert mints a certificate of hosts, the dns names or the ips, signed by parent for usages.
+func NewHostCert(t *testing.T, cn string, hosts []string, parent *Cert, usages ...x509.ExtKeyUsage) *Cert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  usages,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	for _, v := range hosts {
+		if ip := net.ParseIP(v); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, v)
+		}
+	}
+
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Cert, parent.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &Cert{Cert: cert, Key: key, Der: der}
+}
+
+// Write writes the certificate and the key in pem, the key is skipped when keyFile is empty.
+func (c *Cert) Write(t *testing.T, certFile string, keyFile string) {
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Der}), 0600))
+	if keyFile != "" {
+		der, err := x509.MarshalECPrivateKey(c.Key)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	}
+}
+
+func (c *Cert) TLS() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.Der}, PrivateKey: c.Key}
+}
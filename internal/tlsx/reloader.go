@@ -0,0 +1,135 @@
+// Package tlsx holds the TLS files reloading shared by the servers and the clients.
+package tlsx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/ringbrew/gsv/logger"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReloadInterval is how often the files are checked for changes by default.
+const DefaultReloadInterval = time.Minute
+
+// Reloader holds the value loaded from files, it is reloaded by Current once any of them is modified.
+type Reloader[T any] struct {
+	files    []string
+	interval time.Duration
+	load     func() (T, error)
+	log      *logger.NamedLogger
+
+	mu        sync.RWMutex
+	value     T
+	modTime   map[string]time.Time
+	lastCheck time.Time
+}
+
+// NewReloader loads the value by load, the files are checked every interval, DefaultReloadInterval when it is 0,
+// negative disables reloading. The errors of reloading are logged by log, the loaded value is kept then.
+func NewReloader[T any](files []string, interval time.Duration, log *logger.NamedLogger, load func() (T, error)) (*Reloader[T], error) {
+	if interval == 0 {
+		interval = DefaultReloadInterval
+	}
+
+	r := &Reloader[T]{
+		files:    files,
+		interval: interval,
+		load:     load,
+		log:      log,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader[T]) reload() error {
+	modTime := make(map[string]time.Time)
+	for _, v := range r.files {
+		fi, err := os.Stat(v)
+		if err != nil {
+			return fmt.Errorf("tls file error: %w", err)
+		}
+		modTime[v] = fi.ModTime()
+	}
+
+	value, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.value = value
+	r.modTime = modTime
+	r.lastCheck = time.Now()
+
+	return nil
+}
+
+// maybeReload reloads the files when any of them is modified, the loaded ones are kept if reloading fails.
+func (r *Reloader[T]) maybeReload() {
+	if r.interval < 0 {
+		return
+	}
+
+	r.mu.Lock()
+	if time.Since(r.lastCheck) < r.interval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCheck = time.Now()
+	modTime := r.modTime
+	r.mu.Unlock()
+
+	changed := false
+	for _, v := range r.files {
+		if fi, err := os.Stat(v); err == nil && !fi.ModTime().Equal(modTime[v]) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := r.reload(); err != nil {
+		r.log.Error(logger.NewEntry().WithMessage(fmt.Sprintf("tls reload error, keep the loaded certificate: %s", err.Error())))
+		return
+	}
+	r.log.Info(logger.NewEntry().WithMessage(fmt.Sprintf("tls files reloaded: %v", r.files)))
+}
+
+// Current returns the value loaded, the files are reloaded first if any of them is modified.
+func (r *Reloader[T]) Current() T {
+	r.maybeReload()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.value
+}
+
+// LoadKeyPair loads the certificate of certFile and keyFile.
+func LoadKeyPair(certFile string, keyFile string) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("tls load key pair[%s] error: %w", certFile, err)
+	}
+	return &cert, nil
+}
+
+// LoadCertPool loads the pem bundle of file.
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("tls load ca error: %w", err)
+	}
+	result := x509.NewCertPool()
+	if !result.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("tls ca file[%s] has no certificate", file)
+	}
+	return result, nil
+}
@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy is what AsyncLogger does with an entry when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the caller until the buffer has room.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest entry buffered for the new one.
+	OverflowDropOldest
+	// OverflowDropByLevel drops the new entry below AsyncOption.DropLevel, and blocks for the others.
+	OverflowDropByLevel
+)
+
+const (
+	defaultAsyncBufferSize   = 1024
+	defaultAsyncCloseTimeout = 5 * time.Second
+)
+
+var ErrAsyncFlushTimeout = errors.New("async logger flush timeout")
+
+type AsyncOption struct {
+	// BufferSize is the count of the entries buffered, 1024 by default.
+	BufferSize int
+	Overflow   OverflowPolicy
+	// DropLevel is the level below which the entries are dropped by OverflowDropByLevel, LevelWarn by default.
+	DropLevel Level
+	// CloseTimeout is the deadline for Close to flush the buffer, 5s by default.
+	CloseTimeout time.Duration
+}
+
+type asyncItem struct {
+	level Level
+	entry *LogEntry
+}
+
+// AsyncLogger is the Logger buffering the entries in a ring and logging them into next by a worker goroutine,
+// so the callers do not wait for a slow sink.
+type AsyncLogger struct {
+	next Logger
+	opt  AsyncOption
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	ring     []asyncItem
+	head     int
+	count    int
+	closed   bool
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	dropped [LevelOff]atomic.Uint64
+}
+
+// NewAsyncLogger wraps next and starts the worker, Close should be called to flush the buffer before exiting.
+func NewAsyncLogger(next Logger, opt AsyncOption) *AsyncLogger {
+	if opt.BufferSize <= 0 {
+		opt.BufferSize = defaultAsyncBufferSize
+	}
+	if opt.CloseTimeout <= 0 {
+		opt.CloseTimeout = defaultAsyncCloseTimeout
+	}
+	if opt.Overflow == OverflowDropByLevel && opt.DropLevel == LevelDebug {
+		opt.DropLevel = LevelWarn
+	}
+
+	result := &AsyncLogger{
+		next: next,
+		opt:  opt,
+		ring: make([]asyncItem, opt.BufferSize),
+		done: make(chan struct{}),
+	}
+	result.notEmpty = sync.NewCond(&result.mu)
+	result.notFull = sync.NewCond(&result.mu)
+
+	go result.run()
+	return result
+}
+
+func (a *AsyncLogger) push(level Level, entry *LogEntry) {
+	// the caller is not on the stack of the worker.
+	if entry.pc == 0 {
+		entry.pc = callerPC()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for a.count == len(a.ring) && !a.closed {
+		switch {
+		case a.opt.Overflow == OverflowDropOldest:
+			a.dropped[a.ring[a.head].level].Add(1)
+			a.pop()
+		case a.opt.Overflow == OverflowDropByLevel && level < a.opt.DropLevel:
+			a.dropped[level].Add(1)
+			return
+		default:
+			a.notFull.Wait()
+		}
+	}
+
+	if a.closed {
+		a.dropped[level].Add(1)
+		return
+	}
+
+	a.ring[(a.head+a.count)%len(a.ring)] = asyncItem{level: level, entry: entry}
+	a.count++
+	a.notEmpty.Signal()
+}
+
+// pop removes the head of the ring, a.mu is held.
+func (a *AsyncLogger) pop() asyncItem {
+	item := a.ring[a.head]
+	a.ring[a.head] = asyncItem{}
+	a.head = (a.head + 1) % len(a.ring)
+	a.count--
+	return item
+}
+
+func (a *AsyncLogger) run() {
+	defer close(a.done)
+
+	for {
+		a.mu.Lock()
+		for a.count == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if a.count == 0 {
+			a.mu.Unlock()
+			return
+		}
+		item := a.pop()
+		a.notFull.Signal()
+		a.mu.Unlock()
+
+		a.write(item)
+	}
+}
+
+func (a *AsyncLogger) write(item asyncItem) {
+	// a panic of the sink does not stop the worker.
+	defer func() {
+		recover()
+	}()
+
+	switch item.level {
+	case LevelDebug:
+		a.next.Debug(item.entry)
+	case LevelInfo:
+		a.next.Info(item.entry)
+	case LevelWarn:
+		a.next.Warn(item.entry)
+	case LevelError:
+		a.next.Error(item.entry)
+	default:
+		a.next.Fatal(item.entry)
+	}
+}
+
+func (a *AsyncLogger) SetLevel(level Level) {
+	a.next.SetLevel(level)
+}
+
+func (a *AsyncLogger) GetLevel() Level {
+	return a.next.GetLevel()
+}
+
+func (a *AsyncLogger) Debug(entry *LogEntry) {
+	a.push(LevelDebug, entry)
+}
+
+func (a *AsyncLogger) Info(entry *LogEntry) {
+	a.push(LevelInfo, entry)
+}
+
+func (a *AsyncLogger) Warn(entry *LogEntry) {
+	a.push(LevelWarn, entry)
+}
+
+func (a *AsyncLogger) Error(entry *LogEntry) {
+	a.push(LevelError, entry)
+}
+
+func (a *AsyncLogger) Fatal(entry *LogEntry) {
+	a.push(LevelFatal, entry)
+}
+
+// Dropped returns the count of the entries of level dropped, by the overflow policy or after Close.
+func (a *AsyncLogger) Dropped(level Level) uint64 {
+	if level >= LevelOff {
+		return 0
+	}
+	return a.dropped[level].Load()
+}
+
+// DroppedTotal returns the count of the entries dropped of all levels.
+func (a *AsyncLogger) DroppedTotal() uint64 {
+	var result uint64
+	for i := range a.dropped {
+		result += a.dropped[i].Load()
+	}
+	return result
+}
+
+// Buffered returns the count of the entries waiting for the worker.
+func (a *AsyncLogger) Buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.count
+}
+
+// Close stops accepting entries, flushes the buffer into next within AsyncOption.CloseTimeout and closes next.
+// The entries left after the deadline are dropped and ErrAsyncFlushTimeout is returned, next is closed later
+// then, after the write in progress returns.
+func (a *AsyncLogger) Close() error {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		a.notEmpty.Broadcast()
+		a.notFull.Broadcast()
+		a.mu.Unlock()
+
+		timer := time.NewTimer(a.opt.CloseTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-a.done:
+		case <-timer.C:
+			a.mu.Lock()
+			left := a.count
+			for a.count > 0 {
+				item := a.pop()
+				a.dropped[item.level].Add(1)
+			}
+			a.mu.Unlock()
+			a.closeErr = fmt.Errorf("%w: %d entries dropped", ErrAsyncFlushTimeout, left)
+
+			// the worker may be still in a write, next is closed once it returns.
+			go func() {
+				<-a.done
+				a.next.Close()
+			}()
+			return
+		}
+
+		a.closeErr = a.next.Close()
+	})
+	return a.closeErr
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// slowLogger records the messages after blocking on release.
+type slowLogger struct {
+	MockLogger
+	mu       sync.Mutex
+	release  chan struct{}
+	messages []string
+	closed   bool
+}
+
+func (s *slowLogger) record(entry *LogEntry) {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, entry.Message)
+}
+
+func (s *slowLogger) Info(entry *LogEntry)  { s.record(entry) }
+func (s *slowLogger) Warn(entry *LogEntry)  { s.record(entry) }
+func (s *slowLogger) Error(entry *LogEntry) { s.record(entry) }
+
+func (s *slowLogger) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *slowLogger) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *slowLogger) Messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func TestAsyncLoggerDropOldest(t *testing.T) {
+	sink := &slowLogger{release: make(chan struct{})}
+	a := NewAsyncLogger(sink, AsyncOption{BufferSize: 2, Overflow: OverflowDropOldest})
+
+	// the worker holds "1" and the ring keeps the newest two.
+	a.Info(NewEntry().WithMessage("1"))
+	require.Eventually(t, func() bool { return a.Buffered() == 0 }, time.Second, time.Millisecond)
+	for _, v := range []string{"2", "3", "4", "5"} {
+		a.Info(NewEntry().WithMessage(v))
+	}
+	require.Equal(t, uint64(2), a.Dropped(LevelInfo))
+
+	close(sink.release)
+	require.NoError(t, a.Close())
+	require.Equal(t, []string{"1", "4", "5"}, sink.Messages())
+	require.True(t, sink.Closed())
+
+	// the entries after Close are dropped.
+	a.Info(NewEntry().WithMessage("6"))
+	require.Equal(t, uint64(3), a.DroppedTotal())
+}
+
+func TestAsyncLoggerDropByLevel(t *testing.T) {
+	sink := &slowLogger{release: make(chan struct{})}
+	a := NewAsyncLogger(sink, AsyncOption{BufferSize: 1, Overflow: OverflowDropByLevel})
+
+	a.Info(NewEntry().WithMessage("1"))
+	require.Eventually(t, func() bool { return a.Buffered() == 0 }, time.Second, time.Millisecond)
+	a.Info(NewEntry().WithMessage("2"))
+	a.Info(NewEntry().WithMessage("dropped"))
+
+	// the warning waits for room instead.
+	done := make(chan struct{})
+	go func() {
+		a.Warn(NewEntry().WithMessage("warn"))
+		close(done)
+	}()
+	close(sink.release)
+	<-done
+
+	require.NoError(t, a.Close())
+	require.Equal(t, []string{"1", "2", "warn"}, sink.Messages())
+	require.Equal(t, uint64(1), a.Dropped(LevelInfo))
+}
+
+func TestAsyncLoggerCloseTimeout(t *testing.T) {
+	sink := &slowLogger{release: make(chan struct{})}
+	a := NewAsyncLogger(sink, AsyncOption{CloseTimeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		a.Info(NewEntry().WithMessage("slow"))
+	}
+	// the slow sink does not block the callers.
+	require.Less(t, time.Since(start), 20*time.Millisecond)
+
+	err := a.Close()
+	require.True(t, errors.Is(err, ErrAsyncFlushTimeout))
+	require.Equal(t, uint64(9), a.Dropped(LevelInfo))
+
+	// the sink is not closed under the write in progress.
+	time.Sleep(10 * time.Millisecond)
+	require.False(t, sink.Closed())
+	close(sink.release)
+	require.Eventually(t, sink.Closed, time.Second, time.Millisecond)
+	require.Equal(t, []string{"slow"}, sink.Messages())
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// MaxBaggageValueSize is the size in bytes the baggage values logged are truncated to.
+const MaxBaggageValueSize = 256
+
+var baggageKeys atomic.Pointer[map[string]struct{}]
+
+// SetBaggageKeys sets the keys of the baggage members copied into LogEntry.Extra by NewEntry, and into
+// service.Context by tracex, like SetBaggageKeys("tenant", "userId"). The members are propagated from any
+// upstream, so none is copied until they are allowed.
+func SetBaggageKeys(keys ...string) {
+	allowed := make(map[string]struct{}, len(keys))
+	for _, v := range keys {
+		allowed[v] = struct{}{}
+	}
+	baggageKeys.Store(&allowed)
+}
+
+// BaggageKeyAllowed reports whether the baggage member of key is copied, see SetBaggageKeys.
+func BaggageKeyAllowed(key string) bool {
+	allowed := baggageKeys.Load()
+	if allowed == nil {
+		return false
+	}
+	_, ok := (*allowed)[key]
+	return ok
+}
+
+// BaggageMembers returns the baggage members of ctx allowed by SetBaggageKeys, the values are truncated to
+// MaxBaggageValueSize.
+func BaggageMembers(ctx context.Context) map[string]string {
+	allowed := baggageKeys.Load()
+	if allowed == nil || len(*allowed) == 0 {
+		return nil
+	}
+
+	var result map[string]string
+	for _, m := range baggage.FromContext(ctx).Members() {
+		if _, ok := (*allowed)[m.Key()]; !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[m.Key()] = truncateBaggage(m.Value())
+	}
+	return result
+}
+
+func truncateBaggage(v string) string {
+	if len(v) <= MaxBaggageValueSize {
+		return v
+	}
+	n := MaxBaggageValueSize
+	for n > 0 && !utf8.RuneStart(v[n]) {
+		n--
+	}
+	return v[:n]
+}
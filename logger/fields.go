@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	FieldRoute  = "route"
+	FieldMethod = "method"
+	FieldPath   = "path"
+	FieldPeer   = "peer"
+)
+
+type fieldsCtxKey struct{}
+
+// WithFields returns a new Context carrying the key value pairs kv, like WithFields(ctx, "userId", 1, "tenant", "t1").
+// The fields of ctx are kept and the ones of the same key are replaced. NewEntry(ctx) sets them into LogEntry.Extra.
+func WithFields(ctx context.Context, kv ...interface{}) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+
+	parent := fieldsFromContext(ctx)
+	fields := make(map[string]interface{}, len(parent)+len(kv)/2)
+	for k, v := range parent {
+		fields[k] = v
+	}
+
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		if i+1 < len(kv) {
+			fields[key] = kv[i+1]
+		} else {
+			// the odd key is kept with no value, like slog does.
+			fields["!BADKEY"] = kv[i]
+		}
+	}
+
+	return context.WithValue(ctx, fieldsCtxKey{}, fields)
+}
+
+// Fields returns a copy of the fields of ctx set by WithFields.
+func Fields(ctx context.Context) map[string]interface{} {
+	parent := fieldsFromContext(ctx)
+	result := make(map[string]interface{}, len(parent))
+	for k, v := range parent {
+		result[k] = v
+	}
+	return result
+}
+
+func fieldsFromContext(ctx context.Context) map[string]interface{} {
+	fields, _ := ctx.Value(fieldsCtxKey{}).(map[string]interface{})
+	return fields
+}
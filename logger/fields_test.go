@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), "userId", 1, "tenant", "t1")
+	child := WithFields(ctx, "tenant", "t2", FieldRoute, "/pay/{id}")
+
+	entry := NewEntry(child)
+	require.Equal(t, 1, entry.Extra["userId"])
+	require.Equal(t, "t2", entry.Extra["tenant"])
+	require.Equal(t, "/pay/{id}", entry.Extra[FieldRoute])
+
+	// the parent is not changed by the child.
+	require.Equal(t, map[string]interface{}{"userId": 1, "tenant": "t1"}, Fields(ctx))
+
+	require.Equal(t, "odd", Fields(WithFields(context.Background(), "odd"))["!BADKEY"])
+	require.Empty(t, NewEntry(context.Background()).Extra)
+}
+
+func TestBaggageKeys(t *testing.T) {
+	tenant, err := baggage.NewMember("tenant", strings.Repeat("t", MaxBaggageValueSize+1))
+	require.NoError(t, err)
+	secret, err := baggage.NewMember("secret", "s1")
+	require.NoError(t, err)
+	bags, err := baggage.New(tenant, secret)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(context.Background(), bags)
+
+	// none is copied by default.
+	require.Empty(t, NewEntry(ctx).Extra)
+
+	SetBaggageKeys("tenant")
+	defer SetBaggageKeys()
+	entry := NewEntry(ctx)
+	require.Len(t, entry.Extra, 1)
+	require.Len(t, entry.Extra["tenant"], MaxBaggageValueSize)
+}
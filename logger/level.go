@@ -0,0 +1,303 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RootName is the name of the level of the package functions, like Info and Error.
+const RootName = "root"
+
+// levelOverride is the level of a named logger or a request path, reverted at expireAt if set.
+type levelOverride struct {
+	level    Level
+	prev     *Level
+	expireAt time.Time
+	timer    *time.Timer
+}
+
+var levels = struct {
+	sync.RWMutex
+	root Level
+	prev *Level
+	// rootExpireAt and rootTimer revert the root level after a ttl, rootGen tells the timer of the latest set.
+	rootExpireAt time.Time
+	rootTimer    *time.Timer
+	rootGen      uint64
+	// named holds the levels of the named loggers, and the ones of the request paths by the names starting with '/'.
+	named map[string]*levelOverride
+}{
+	root:  LevelInfo,
+	named: make(map[string]*levelOverride),
+}
+
+// ParseLevel parses the level from its name, like 'debug' or 'INFO'.
+func ParseLevel(s string) (Level, error) {
+	for lv := LevelDebug; lv <= LevelOff; lv++ {
+		if strings.EqualFold(s, lv.String()) {
+			return lv, nil
+		}
+	}
+	return LevelOff, fmt.Errorf("unknown log level[%s]", s)
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	lv, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = lv
+	return nil
+}
+
+// SetNamedLevel sets the level of the logger of name, it is reverted after ttl when ttl > 0. The name RootName
+// sets the level of the package functions, and the names starting with '/' set the level of the entries of
+// a request path, like '/pay/{id}', '/pay/*' or '/pkg.Service/Method'.
+func SetNamedLevel(name string, level Level, ttl time.Duration) {
+	levels.Lock()
+	if name == RootName || name == "" {
+		// the timer may have fired already, the revert waiting for the lock is told stale by rootGen.
+		if levels.rootTimer != nil {
+			levels.rootTimer.Stop()
+		} else {
+			prev := levels.root
+			levels.prev = &prev
+		}
+		levels.rootGen++
+		levels.root = level
+		levels.rootExpireAt = time.Time{}
+		levels.rootTimer = nil
+		if ttl > 0 {
+			levels.rootExpireAt = time.Now().Add(ttl)
+			levels.rootTimer = revertRootAfter(ttl, levels.rootGen)
+		} else {
+			levels.prev = nil
+		}
+	} else {
+		// a new override for every set, so the revert of the replaced one waiting for the lock is a no-op.
+		o := &levelOverride{level: level}
+		if prev, ok := levels.named[name]; ok {
+			if prev.timer != nil {
+				prev.timer.Stop()
+				o.prev = prev.prev
+			} else {
+				v := prev.level
+				o.prev = &v
+			}
+		}
+		if ttl > 0 {
+			o.expireAt = time.Now().Add(ttl)
+			o.timer = time.AfterFunc(ttl, func() { revertNamedLevel(name, o) })
+		} else {
+			o.prev = nil
+		}
+		levels.named[name] = o
+	}
+	levels.Unlock()
+
+	applyLevel()
+}
+
+// ResetNamedLevel removes the level of name, the logger follows the root level after it.
+func ResetNamedLevel(name string) {
+	levels.Lock()
+	if o, ok := levels.named[name]; ok {
+		if o.timer != nil {
+			o.timer.Stop()
+		}
+		delete(levels.named, name)
+	}
+	levels.Unlock()
+
+	applyLevel()
+}
+
+func revertRootAfter(ttl time.Duration, gen uint64) *time.Timer {
+	return time.AfterFunc(ttl, func() { revertRootLevel(gen) })
+}
+
+func revertRootLevel(gen uint64) {
+	levels.Lock()
+	// the level may be set again already.
+	if levels.rootGen != gen {
+		levels.Unlock()
+		return
+	}
+	if levels.prev != nil {
+		levels.root = *levels.prev
+	}
+	levels.prev = nil
+	levels.rootTimer = nil
+	levels.rootExpireAt = time.Time{}
+	levels.Unlock()
+
+	applyLevel()
+}
+
+func revertNamedLevel(name string, o *levelOverride) {
+	levels.Lock()
+	// the override may be replaced already.
+	if levels.named[name] == o {
+		if o.prev != nil {
+			levels.named[name] = &levelOverride{level: *o.prev}
+		} else {
+			delete(levels.named, name)
+		}
+	}
+	levels.Unlock()
+
+	applyLevel()
+}
+
+// NamedLevel is a level set, as listed by Levels.
+type NamedLevel struct {
+	Name     string    `json:"name"`
+	Level    Level     `json:"level"`
+	ExpireAt time.Time `json:"expireAt,omitempty"`
+}
+
+// Levels lists the root level and the ones set by SetNamedLevel.
+func Levels() []NamedLevel {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	result := make([]NamedLevel, 0, len(levels.named)+1)
+	result = append(result, NamedLevel{Name: RootName, Level: levels.root, ExpireAt: levels.rootExpireAt})
+	for name, o := range levels.named {
+		result = append(result, NamedLevel{Name: name, Level: o.level, ExpireAt: o.expireAt})
+	}
+	sort.Slice(result[1:], func(i, j int) bool {
+		return result[i+1].Name < result[j+1].Name
+	})
+	return result
+}
+
+// levelSnapshot is the state of the levels saved by saveLevels.
+type levelSnapshot struct {
+	root         Level
+	prev         *Level
+	rootExpireAt time.Time
+	named        map[string]levelOverride
+}
+
+func saveLevels() levelSnapshot {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	result := levelSnapshot{
+		root:         levels.root,
+		prev:         levels.prev,
+		rootExpireAt: levels.rootExpireAt,
+		named:        make(map[string]levelOverride, len(levels.named)),
+	}
+	for name, o := range levels.named {
+		result.named[name] = levelOverride{level: o.level, prev: o.prev, expireAt: o.expireAt}
+	}
+	return result
+}
+
+// restoreLevels reverts the levels to s, the ttl of the ones set with it runs on from the time s was saved.
+func restoreLevels(s levelSnapshot) {
+	levels.Lock()
+	if levels.rootTimer != nil {
+		levels.rootTimer.Stop()
+	}
+	for _, o := range levels.named {
+		if o.timer != nil {
+			o.timer.Stop()
+		}
+	}
+
+	levels.root = s.root
+	levels.prev = s.prev
+	levels.rootExpireAt = s.rootExpireAt
+	levels.rootTimer = nil
+	levels.rootGen++
+	if !s.rootExpireAt.IsZero() {
+		levels.rootTimer = revertRootAfter(time.Until(s.rootExpireAt), levels.rootGen)
+	}
+
+	levels.named = make(map[string]*levelOverride, len(s.named))
+	for name, v := range s.named {
+		o := &levelOverride{level: v.level, prev: v.prev, expireAt: v.expireAt}
+		if !o.expireAt.IsZero() {
+			o.timer = time.AfterFunc(time.Until(o.expireAt), func() { revertNamedLevel(name, o) })
+		}
+		levels.named[name] = o
+	}
+	levels.Unlock()
+
+	applyLevel()
+}
+
+// applyLevel sets the Logger to the lowest level set, the entries are filtered by the levels of the package
+// before they reach it.
+func applyLevel() {
+	levels.RLock()
+	floor := levels.root
+	for _, o := range levels.named {
+		if o.level < floor {
+			floor = o.level
+		}
+	}
+	levels.RUnlock()
+
+	l.SetLevel(floor)
+}
+
+// enabled reports whether the entry of level is logged by the logger of name, the level of the request path of
+// the entry goes first, then the level of name, then the root level.
+func enabled(name string, level Level, entry *LogEntry) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	min := levels.root
+	if o, ok := levels.named[name]; ok && name != "" {
+		min = o.level
+	}
+	if o, ok := pathLevel(entry); ok {
+		min = o
+	}
+	return min != LevelOff && level >= min
+}
+
+// pathKeys are the keys of LogEntry.Extra holding the request path, set by the interceptors of server.
+var pathKeys = []string{FieldRoute, FieldPath, FieldMethod}
+
+func pathLevel(entry *LogEntry) (Level, bool) {
+	if len(levels.named) == 0 || entry == nil {
+		return 0, false
+	}
+
+	for _, key := range pathKeys {
+		path, ok := entry.Extra[key].(string)
+		if !ok || !strings.HasPrefix(path, "/") {
+			continue
+		}
+		if o, ok := levels.named[path]; ok {
+			return o.level, true
+		}
+		// the longest prefix matched wins, like '/pay/card/*' over '/pay/*'.
+		var matched *levelOverride
+		longest := -1
+		for name, o := range levels.named {
+			if !strings.HasSuffix(name, "*") || !strings.HasPrefix(name, "/") {
+				continue
+			}
+			if prefix := strings.TrimSuffix(name, "*"); strings.HasPrefix(path, prefix) && len(prefix) > longest {
+				matched, longest = o, len(prefix)
+			}
+		}
+		if matched != nil {
+			return matched.level, true
+		}
+	}
+	return 0, false
+}
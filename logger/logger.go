@@ -3,24 +3,27 @@ package logger
 import (
 	"context"
 	"fmt"
-	"github.com/ringbrew/gsv/service"
+	"github.com/ringbrew/gsv/internal/gsvctx"
 )
 
 var l Logger
 
+// SetLogger sets the Logger of the package, the levels set by SetLevel and SetNamedLevel are kept and applied to ll.
 func SetLogger(ll Logger) {
 	l = ll
-
-	// 默认日志级别info
-	l.SetLevel(LevelInfo)
+	applyLevel()
 }
 
+// SetLevel sets the root level, which is LevelInfo by default.
 func SetLevel(ll Level) {
-	l.SetLevel(ll)
+	SetNamedLevel(RootName, ll, 0)
 }
 
+// GetLevel returns the root level.
 func GetLevel() Level {
-	return l.GetLevel()
+	levels.RLock()
+	defer levels.RUnlock()
+	return levels.root
 }
 
 type Level uint8
@@ -59,6 +62,9 @@ type LogEntry struct {
 	ParentId string
 	Message  string
 	Extra    map[string]interface{}
+
+	// pc is the caller logging the entry, kept by the Loggers writing on another goroutine, see callerPC.
+	pc uintptr
 }
 
 func NewEntry(ctx ...context.Context) *LogEntry {
@@ -67,11 +73,20 @@ func NewEntry(ctx ...context.Context) *LogEntry {
 	}
 
 	if len(ctx) > 0 {
-		if rpcCtx, ok := service.FromContext(ctx[0]); ok {
+		if rpcCtx, ok := gsvctx.FromContext(ctx[0]); ok {
 			result.TraceId = rpcCtx.TraceId()
 			result.SpanId = rpcCtx.SpanId()
 			result.ParentId = rpcCtx.ParentId()
 		}
+
+		// the baggage members allowed by SetBaggageKeys, like the tenant or user id, are logged with the entry.
+		for k, v := range BaggageMembers(ctx[0]) {
+			result.Extra[k] = v
+		}
+
+		for k, v := range fieldsFromContext(ctx[0]) {
+			result.Extra[k] = v
+		}
 	}
 	return result
 }
@@ -108,24 +123,35 @@ func NewDefaultLogger() Logger {
 
 func init() {
 	l = NewDefaultLogger()
+	applyLevel()
 }
 
 func Debug(entry *LogEntry) {
-	l.Debug(entry)
+	if enabled("", LevelDebug, entry) {
+		l.Debug(entry)
+	}
 }
 
 func Info(entry *LogEntry) {
-	l.Info(entry)
+	if enabled("", LevelInfo, entry) {
+		l.Info(entry)
+	}
 }
 
 func Warn(entry *LogEntry) {
-	l.Warn(entry)
+	if enabled("", LevelWarn, entry) {
+		l.Warn(entry)
+	}
 }
 
 func Error(entry *LogEntry) {
-	l.Error(entry)
+	if enabled("", LevelError, entry) {
+		l.Error(entry)
+	}
 }
 
 func Fatal(entry *LogEntry) {
-	l.Fatal(entry)
+	if enabled("", LevelFatal, entry) {
+		l.Fatal(entry)
+	}
 }
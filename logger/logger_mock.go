@@ -1,6 +1,13 @@
 package logger
 
-import "log"
+import (
+	"log"
+	"os"
+)
+
+// mockOutput is not the std logger, which would be re-entered when the std log is routed by slog.SetDefault
+// into NewSlogHandler.
+var mockOutput = log.New(os.Stderr, "", log.LstdFlags)
 
 type MockLogger struct {
 	level Level
@@ -15,23 +22,23 @@ func (m *MockLogger) GetLevel() Level {
 }
 
 func (m *MockLogger) Debug(entry *LogEntry) {
-	log.Printf("[DEBUG]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
+	mockOutput.Printf("[DEBUG]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
 }
 
 func (m *MockLogger) Info(entry *LogEntry) {
-	log.Printf("[INFO]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
+	mockOutput.Printf("[INFO]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
 }
 
 func (m *MockLogger) Warn(entry *LogEntry) {
-	log.Printf("[WARN]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
+	mockOutput.Printf("[WARN]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
 }
 
 func (m *MockLogger) Error(entry *LogEntry) {
-	log.Printf("[ERROR]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
+	mockOutput.Printf("[ERROR]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
 }
 
 func (m *MockLogger) Fatal(entry *LogEntry) {
-	log.Printf("[FATAL]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
+	mockOutput.Printf("[FATAL]%v-%v-%v-%v\n", entry.TraceId, entry.SpanId, entry.Extra, entry.Message)
 }
 
 func (m *MockLogger) Close() error {
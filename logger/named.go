@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NameKey is the key of LogEntry.Extra holding the name of the logger.
+const NameKey = "logger"
+
+// DefaultLevelPath is the path of LevelHandler served by server.LogLevelOption.
+const DefaultLevelPath = "/admin/log/level"
+
+// NamedLogger logs into the Logger of the package by the level of its name, which follows the root level
+// until it is set.
+type NamedLogger struct {
+	name string
+}
+
+var (
+	namedMu      sync.Mutex
+	namedLoggers = make(map[string]*NamedLogger)
+)
+
+// Named returns the logger of name, like Named("discovery"), the same one is returned for the same name.
+func Named(name string) *NamedLogger {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+
+	if nl, ok := namedLoggers[name]; ok {
+		return nl
+	}
+	nl := &NamedLogger{name: name}
+	namedLoggers[name] = nl
+	return nl
+}
+
+func (nl *NamedLogger) Name() string {
+	return nl.name
+}
+
+// SetLevel sets the level of the logger, like SetNamedLevel(name, level, 0).
+func (nl *NamedLogger) SetLevel(level Level) {
+	SetNamedLevel(nl.name, level, 0)
+}
+
+// GetLevel returns the level of the logger, the root level if it is not set.
+func (nl *NamedLogger) GetLevel() Level {
+	levels.RLock()
+	defer levels.RUnlock()
+	if o, ok := levels.named[nl.name]; ok {
+		return o.level
+	}
+	return levels.root
+}
+
+// Enabled reports whether the entry of level is logged, for the values costly to build only when they are.
+func (nl *NamedLogger) Enabled(level Level, entry *LogEntry) bool {
+	return enabled(nl.name, level, entry)
+}
+
+func (nl *NamedLogger) log(level Level, entry *LogEntry, f func(entry *LogEntry)) {
+	if !enabled(nl.name, level, entry) {
+		return
+	}
+	if _, ok := entry.Extra[NameKey]; !ok {
+		entry.WithExtra(NameKey, nl.name)
+	}
+	f(entry)
+}
+
+func (nl *NamedLogger) Debug(entry *LogEntry) {
+	nl.log(LevelDebug, entry, l.Debug)
+}
+
+func (nl *NamedLogger) Info(entry *LogEntry) {
+	nl.log(LevelInfo, entry, l.Info)
+}
+
+func (nl *NamedLogger) Warn(entry *LogEntry) {
+	nl.log(LevelWarn, entry, l.Warn)
+}
+
+func (nl *NamedLogger) Error(entry *LogEntry) {
+	nl.log(LevelError, entry, l.Error)
+}
+
+func (nl *NamedLogger) Fatal(entry *LogEntry) {
+	nl.log(LevelFatal, entry, l.Fatal)
+}
+
+type levelRequest struct {
+	Name  string `json:"name"`
+	Level *Level `json:"level"`
+	// TTL reverts the level after it, like '10m', it is kept until changed when empty.
+	TTL string `json:"ttl"`
+}
+
+// LevelHandler is the admin endpoint of the levels:
+//
+//	GET     lists the levels.
+//	PUT     sets a level by {"name": "discovery", "level": "debug", "ttl": "10m"}, the name is RootName,
+//	        the one of a NamedLogger or a request path, see SetNamedLevel. The name and the level are required.
+//	DELETE  resets the level of ?name=discovery.
+//
+// It changes the verbosity of the whole process, so it should be served on an internal port or behind auth.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+			// the name and the level are required, so a request missing them does not change the root level or
+			// turn on LevelDebug by the zero value.
+			if req.Name == "" {
+				http.Error(rw, "name is required, use 'root' for the root level", http.StatusBadRequest)
+				return
+			}
+			if req.Level == nil {
+				http.Error(rw, "level is required", http.StatusBadRequest)
+				return
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				v, err := time.ParseDuration(req.TTL)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+				ttl = v
+			}
+			SetNamedLevel(req.Name, *req.Level, ttl)
+			Info(NewEntry().WithMessageF("log level of [%s] set to %s, ttl[%s]", req.Name, *req.Level, req.TTL))
+		case http.MethodDelete:
+			name := r.URL.Query().Get("name")
+			if name == "" || name == RootName {
+				http.Error(rw, "name of a named level is required", http.StatusBadRequest)
+				return
+			}
+			ResetNamedLevel(name)
+		default:
+			rw.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(Levels())
+	})
+}
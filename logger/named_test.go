@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamedLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l, err := NewSlogLogger(SlogOption{Output: buf, Format: FormatText})
+	require.NoError(t, err)
+	SetLogger(l)
+	defer SetLogger(NewDefaultLogger())
+	defer ResetNamedLevel("discovery")
+	defer ResetNamedLevel("/pay/*")
+
+	discovery := Named("discovery")
+	require.Same(t, discovery, Named("discovery"))
+
+	discovery.Debug(NewEntry().WithMessage("dropped"))
+	Debug(NewEntry().WithMessage("dropped"))
+	require.Empty(t, buf.String())
+
+	// the named logger is raised alone.
+	discovery.SetLevel(LevelDebug)
+	discovery.Debug(NewEntry().WithMessage("discovery debug"))
+	Debug(NewEntry().WithMessage("dropped"))
+	require.Contains(t, buf.String(), "discovery debug")
+	require.Contains(t, buf.String(), "logger=discovery")
+	require.NotContains(t, buf.String(), "dropped")
+	require.Equal(t, LevelInfo, GetLevel())
+
+	// and a request path.
+	SetNamedLevel("/pay/*", LevelDebug, 0)
+	Debug(NewEntry().WithExtra(FieldPath, "/pay/1").WithMessage("pay debug"))
+	Debug(NewEntry().WithExtra(FieldPath, "/user/1").WithMessage("dropped"))
+	require.Contains(t, buf.String(), "pay debug")
+	require.NotContains(t, buf.String(), "dropped")
+
+	// the longest prefix wins.
+	defer ResetNamedLevel("/pay/card/*")
+	SetNamedLevel("/pay/card/*", LevelError, 0)
+	for i := 0; i < 20; i++ {
+		Info(NewEntry().WithExtra(FieldPath, "/pay/card/1").WithMessage("dropped"))
+	}
+	require.NotContains(t, buf.String(), "dropped")
+
+	// the level set by ttl is reverted.
+	SetNamedLevel("discovery", LevelError, 20*time.Millisecond)
+	require.Equal(t, LevelError, discovery.GetLevel())
+	require.Eventually(t, func() bool {
+		return discovery.GetLevel() == LevelDebug
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer ResetNamedLevel("cli")
+	h := LevelHandler()
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, DefaultLevelPath, strings.NewReader(`{"name":"cli","level":"warn","ttl":"1m"}`)))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, LevelWarn, Named("cli").GetLevel())
+
+	var result []NamedLevel
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &result))
+	require.Equal(t, RootName, result[0].Name)
+	require.Contains(t, rw.Body.String(), `"name":"cli","level":"WARN"`)
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, DefaultLevelPath, strings.NewReader(`{"name":"cli","level":"verbose"}`)))
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+
+	// the name and the level are required.
+	for _, body := range []string{`{"name":"cli"}`, `{"level":"debug"}`} {
+		rw = httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, DefaultLevelPath, strings.NewReader(body)))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+	}
+	require.Equal(t, LevelWarn, Named("cli").GetLevel())
+	require.Equal(t, LevelInfo, GetLevel())
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodDelete, DefaultLevelPath+"?name=cli", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, GetLevel(), Named("cli").GetLevel())
+}
+
+func TestNamedLevelStaleRevert(t *testing.T) {
+	defer ResetNamedLevel("stale")
+	defer SetLevel(GetLevel())
+
+	// the timers fired before the level is set again, their reverts wait for the lock meanwhile.
+	SetNamedLevel("stale", LevelDebug, time.Minute)
+	o := levels.named["stale"]
+	SetNamedLevel("stale", LevelWarn, 0)
+	revertNamedLevel("stale", o)
+	require.Equal(t, LevelWarn, Named("stale").GetLevel())
+
+	SetNamedLevel(RootName, LevelDebug, time.Minute)
+	gen := levels.rootGen
+	SetLevel(LevelError)
+	revertRootLevel(gen)
+	require.Equal(t, LevelError, GetLevel())
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedEntry is an entry kept by Recorder with its level.
+type RecordedEntry struct {
+	*LogEntry
+	Level Level
+	Time  time.Time
+}
+
+// RecordedEntries are the entries of Recorder, filtered by the query helpers, like
+// rec.Entries().Level(LevelError).Extra("method", "/pkg.Service/Get").
+type RecordedEntries []RecordedEntry
+
+func (es RecordedEntries) filter(f func(e RecordedEntry) bool) RecordedEntries {
+	result := make(RecordedEntries, 0, len(es))
+	for _, e := range es {
+		if f(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Level returns the entries of level.
+func (es RecordedEntries) Level(level Level) RecordedEntries {
+	return es.filter(func(e RecordedEntry) bool { return e.Level == level })
+}
+
+// Message returns the entries of the message msg.
+func (es RecordedEntries) Message(msg string) RecordedEntries {
+	return es.filter(func(e RecordedEntry) bool { return e.Message == msg })
+}
+
+// MessageContains returns the entries whose message contains sub.
+func (es RecordedEntries) MessageContains(sub string) RecordedEntries {
+	return es.filter(func(e RecordedEntry) bool { return strings.Contains(e.Message, sub) })
+}
+
+// HasExtra returns the entries with the extra key.
+func (es RecordedEntries) HasExtra(key string) RecordedEntries {
+	return es.filter(func(e RecordedEntry) bool {
+		_, ok := e.Extra[key]
+		return ok
+	})
+}
+
+// Extra returns the entries with the extra key of value.
+func (es RecordedEntries) Extra(key string, value interface{}) RecordedEntries {
+	return es.filter(func(e RecordedEntry) bool {
+		v, ok := e.Extra[key]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// TraceId returns the entries of the trace, it asserts the correlation of the entries logged in a request.
+func (es RecordedEntries) TraceId(traceId string) RecordedEntries {
+	return es.filter(func(e RecordedEntry) bool { return e.TraceId == traceId })
+}
+
+func (es RecordedEntries) Len() int {
+	return len(es)
+}
+
+func (es RecordedEntries) Messages() []string {
+	result := make([]string, 0, len(es))
+	for _, e := range es {
+		result = append(result, e.Message)
+	}
+	return result
+}
+
+// Recorder is the Logger keeping the entries in memory for the tests, it is safe for concurrent use.
+type Recorder struct {
+	mu      sync.RWMutex
+	level   Level
+	entries RecordedEntries
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record installs a Recorder as the Logger until the test of t ends, the previous Logger and the levels, the root
+// one and the ones of SetNamedLevel, are restored then. The root level is set to LevelDebug meanwhile.
+func Record(t interface{ Cleanup(func()) }) *Recorder {
+	prev := l
+	prevLevels := saveLevels()
+
+	r := NewRecorder()
+	SetLogger(r)
+	SetLevel(LevelDebug)
+
+	t.Cleanup(func() {
+		SetLogger(prev)
+		restoreLevels(prevLevels)
+	})
+	return r
+}
+
+func (r *Recorder) record(level Level, entry *LogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, RecordedEntry{LogEntry: entry, Level: level, Time: time.Now()})
+}
+
+func (r *Recorder) SetLevel(level Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.level = level
+}
+
+func (r *Recorder) GetLevel() Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.level
+}
+
+func (r *Recorder) Debug(entry *LogEntry) {
+	r.record(LevelDebug, entry)
+}
+
+func (r *Recorder) Info(entry *LogEntry) {
+	r.record(LevelInfo, entry)
+}
+
+func (r *Recorder) Warn(entry *LogEntry) {
+	r.record(LevelWarn, entry)
+}
+
+func (r *Recorder) Error(entry *LogEntry) {
+	r.record(LevelError, entry)
+}
+
+func (r *Recorder) Fatal(entry *LogEntry) {
+	r.record(LevelFatal, entry)
+}
+
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Entries returns a copy of the entries recorded, in the order logged.
+func (r *Recorder) Entries() RecordedEntries {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append(RecordedEntries(nil), r.entries...)
+}
+
+// Reset drops the entries recorded.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ringbrew/gsv/internal/gsvctx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var prev Logger
+	defer ResetNamedLevel("discovery")
+	SetNamedLevel("discovery", LevelWarn, time.Minute)
+	prevLevels := Levels()
+	t.Run("record", func(t *testing.T) {
+		prev = l
+		rec := Record(t)
+		SetNamedLevel("discovery", LevelDebug, 0)
+		SetNamedLevel("/pay/*", LevelDebug, 0)
+
+		ctx := gsvctx.NewContext(context.Background(), testCtx{})
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				Debug(NewEntry(ctx).WithMessage("debug").WithExtra("k", []int{1}))
+			}()
+		}
+		wg.Wait()
+		Named("test").Error(NewEntry().WithMessage("failed to pay"))
+
+		es := rec.Entries()
+		require.Equal(t, 11, es.Len())
+		require.Equal(t, 10, es.Level(LevelDebug).TraceId("t1").Extra("k", []int{1}).Len())
+		require.Equal(t, []string{"failed to pay"}, es.Level(LevelError).MessageContains("pay").HasExtra(NameKey).Messages())
+		require.Empty(t, es.Message("none"))
+
+		rec.Reset()
+		require.Empty(t, rec.Entries())
+	})
+
+	// the Logger and the levels are restored after the test.
+	require.Same(t, prev, l)
+	require.Equal(t, LevelInfo, GetLevel())
+	require.Equal(t, prevLevels, Levels())
+}
@@ -0,0 +1,300 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+const (
+	DefaultRedactMask        = "***"
+	DefaultRedactMaxBodySize = 4096
+)
+
+// DefaultRedactHeaders are the headers masked by DefaultRedactPolicy.
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// RedactPolicy masks the sensitive values of the requests and responses logged by the interceptors.
+type RedactPolicy struct {
+	// Headers are the names of the headers masked, case-insensitive.
+	Headers []string
+	// Fields are the paths of the JSON and form fields masked. The ones starting with '$.' are matched from the root,
+	// like '$.password', the others are matched at any depth, like 'card.number'. A '*' matches any one key, and
+	// the array indexes are not part of the paths.
+	Fields []string
+	// Mask replaces the values masked, '***' by default.
+	Mask string
+	// MaxBodySize is the size in bytes the bodies are truncated to, 0 disables it.
+	MaxBodySize int
+	// ProtoRedact masks the protobuf fields with the 'debug_redact' option, like
+	// 'string password = 1 [debug_redact = true];'.
+	ProtoRedact bool
+}
+
+// DefaultRedactPolicy masks DefaultRedactHeaders and the protobuf fields with 'debug_redact', and truncates the
+// bodies to 4KB.
+func DefaultRedactPolicy() *RedactPolicy {
+	return &RedactPolicy{
+		Headers:     DefaultRedactHeaders,
+		Mask:        DefaultRedactMask,
+		MaxBodySize: DefaultRedactMaxBodySize,
+		ProtoRedact: true,
+	}
+}
+
+var redactPolicy atomic.Pointer[RedactPolicy]
+
+func init() {
+	redactPolicy.Store(DefaultRedactPolicy())
+}
+
+// SetRedactPolicy sets the policy shared by the log interceptors of server and cli, nil disables the redaction.
+func SetRedactPolicy(p *RedactPolicy) {
+	if p == nil {
+		p = &RedactPolicy{}
+	}
+	redactPolicy.Store(p)
+}
+
+// Redaction returns the policy set by SetRedactPolicy, DefaultRedactPolicy by default.
+func Redaction() *RedactPolicy {
+	return redactPolicy.Load()
+}
+
+func (p *RedactPolicy) mask() string {
+	if p.Mask == "" {
+		return DefaultRedactMask
+	}
+	return p.Mask
+}
+
+// Header returns a copy of h with the values of the headers of the policy masked.
+func (p *RedactPolicy) Header(h http.Header) http.Header {
+	result := h.Clone()
+	for _, name := range p.Headers {
+		if vs, ok := result[http.CanonicalHeaderKey(name)]; ok {
+			masked := make([]string, len(vs))
+			for i := range masked {
+				masked[i] = p.mask()
+			}
+			result[http.CanonicalHeaderKey(name)] = masked
+		}
+	}
+	return result
+}
+
+// Body returns the body masked by the content type and truncated to MaxBodySize.
+func (p *RedactPolicy) Body(contentType string, body []byte) string {
+	contentType = strings.ToLower(contentType)
+	switch {
+	case strings.Contains(contentType, "json"):
+		return p.JSON(body)
+	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+		return p.Form(body)
+	default:
+		return p.truncate(string(body))
+	}
+}
+
+// JSON returns the JSON document with the fields of the policy masked, the invalid ones are truncated only.
+func (p *RedactPolicy) JSON(body []byte) string {
+	if len(p.Fields) == 0 {
+		return p.truncate(string(body))
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return p.truncate(string(body))
+	}
+	return p.truncate(p.marshal(p.maskValue(nil, v)))
+}
+
+// Form returns the url encoded form with the fields of the policy masked, the fields are matched by their names.
+func (p *RedactPolicy) Form(body []byte) string {
+	values, err := url.ParseQuery(string(body))
+	if err != nil || len(p.Fields) == 0 {
+		return p.truncate(string(body))
+	}
+	p.maskForm(values)
+	return p.truncate(values.Encode())
+}
+
+// PartialBody returns body captured as the prefix of a body of size bytes, with a marker of the bytes not
+// captured. The partial JSON can not be parsed to mask the fields, so it is left out when Fields are set.
+func (p *RedactPolicy) PartialBody(contentType string, body []byte, size int64) string {
+	cut := size - int64(len(body))
+	if cut <= 0 {
+		return p.Body(contentType, body)
+	}
+
+	contentType = strings.ToLower(contentType)
+	prefix := string(body)
+	if len(p.Fields) > 0 {
+		switch {
+		case strings.Contains(contentType, "json"):
+			prefix = ""
+		case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+			values, err := url.ParseQuery(prefix)
+			if err != nil {
+				prefix = ""
+			} else {
+				p.maskForm(values)
+				prefix = values.Encode()
+			}
+		}
+	}
+	// the rune cut by the capture is dropped.
+	for i := len(prefix) - 1; i >= 0 && i >= len(prefix)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(prefix[i]) {
+			if !utf8.FullRuneInString(prefix[i:]) {
+				prefix = prefix[:i]
+			}
+			break
+		}
+	}
+	return fmt.Sprintf("%s...(truncated %d bytes)", prefix, cut)
+}
+
+func (p *RedactPolicy) maskForm(values url.Values) {
+	for k := range values {
+		if p.matchField(strings.Split(k, ".")) {
+			values[k] = []string{p.mask()}
+		}
+	}
+}
+
+// Value returns v for logging, the proto messages are marshalled by protojson with the 'debug_redact' fields
+// masked, and the fields of the policy are masked in the JSON of v.
+func (p *RedactPolicy) Value(v interface{}) string {
+	if m, ok := v.(proto.Message); ok {
+		if p.ProtoRedact {
+			m = proto.Clone(m)
+			p.redactProto(m.ProtoReflect())
+		}
+		data, err := protojson.Marshal(m)
+		if err != nil {
+			return p.truncate(fmt.Sprintf("%v", v))
+		}
+		return p.JSON(data)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return p.truncate(fmt.Sprintf("%v", v))
+	}
+	return p.JSON(data)
+}
+
+// redactProto clears the fields with 'debug_redact' of m, the string fields are set to the mask.
+func (p *RedactPolicy) redactProto(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDebugRedact() {
+			if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+				m.Set(fd, protoreflect.ValueOfString(p.mask()))
+			} else {
+				m.Clear(fd)
+			}
+			return true
+		}
+
+		if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+			return true
+		}
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				p.redactProto(list.Get(i).Message())
+			}
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					p.redactProto(mv.Message())
+					return true
+				})
+			}
+		default:
+			p.redactProto(v.Message())
+		}
+		return true
+	})
+}
+
+func (p *RedactPolicy) maskValue(path []string, v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, child := range vv {
+			childPath := append(path[:len(path):len(path)], k)
+			if p.matchField(childPath) {
+				vv[k] = p.mask()
+			} else {
+				vv[k] = p.maskValue(childPath, child)
+			}
+		}
+	case []interface{}:
+		for i, child := range vv {
+			vv[i] = p.maskValue(path, child)
+		}
+	}
+	return v
+}
+
+// matchField reports whether path matches one of the fields of the policy.
+func (p *RedactPolicy) matchField(path []string) bool {
+	for _, field := range p.Fields {
+		anchored := strings.HasPrefix(field, "$.")
+		parts := strings.Split(strings.TrimPrefix(field, "$."), ".")
+		if anchored && len(parts) != len(path) {
+			continue
+		}
+		if len(parts) > len(path) {
+			continue
+		}
+		// the unanchored fields match the end of path.
+		tail := path[len(path)-len(parts):]
+		matched := true
+		for i := range parts {
+			if parts[i] != "*" && !strings.EqualFold(parts[i], tail[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RedactPolicy) marshal(v interface{}) string {
+	buf := &bytes.Buffer{}
+	e := json.NewEncoder(buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// truncate cuts s to MaxBodySize with a marker of the bytes cut.
+func (p *RedactPolicy) truncate(s string) string {
+	if p.MaxBodySize <= 0 || len(s) <= p.MaxBodySize {
+		return s
+	}
+	n := p.MaxBodySize
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s...(truncated %d bytes)", s[:n], len(s)-n)
+}
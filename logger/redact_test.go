@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestRedactPolicy(t *testing.T) {
+	p := DefaultRedactPolicy()
+	p.Fields = []string{"$.password", "card.number", "tokens.*"}
+
+	h := http.Header{}
+	h.Set("Authorization", "Bearer secret")
+	h.Set("Accept", "application/json")
+	masked := p.Header(h)
+	require.Equal(t, "***", masked.Get("Authorization"))
+	require.Equal(t, "application/json", masked.Get("Accept"))
+	require.Equal(t, "Bearer secret", h.Get("Authorization"))
+
+	body := `{"password":"p1","user":{"password":"kept","card":{"number":"4111","cvv":"1"}},"list":[{"card":{"number":"4222"}}],"tokens":{"a":"t"}}`
+	require.Equal(t,
+		`{"list":[{"card":{"number":"***"}}],"password":"***","tokens":{"a":"***"},"user":{"card":{"cvv":"1","number":"***"},"password":"kept"}}`,
+		p.Body("application/json; charset=utf-8", []byte(body)))
+
+	require.Equal(t, "password=%2A%2A%2A&user=u1", p.Body("application/x-www-form-urlencoded", []byte("password=p1&user=u1")))
+
+	p.MaxBodySize = 8
+	require.Equal(t, "not json...(truncated 6 bytes)", p.Body("text/plain", []byte("not json body!")))
+
+	// the partial bodies captured, the JSON is left out as its fields can not be masked.
+	require.Equal(t, `{"a":1}`, p.PartialBody("application/json", []byte(`{"a":1}`), 7))
+	require.Equal(t, "...(truncated 20 bytes)", p.PartialBody("application/json", []byte(`{"passwo`), 28))
+	require.Equal(t, "password=%2A%2A%2A&u=...(truncated 4 bytes)", p.PartialBody("application/x-www-form-urlencoded", []byte("password=p1&u="), 18))
+	require.Equal(t, "ab...(truncated 3 bytes)", p.PartialBody("text/plain", []byte("ab\xe4\xb8"), 7))
+}
+
+func TestRedactProto(t *testing.T) {
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("redact_test.proto"),
+		Package: proto.String("gsv.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Login"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user"), JsonName: proto.String("user"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("password"), JsonName: proto.String("password"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Options: &descriptorpb.FieldOptions{DebugRedact: proto.Bool(true)}},
+			},
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	md := fd.Messages().ByName("Login")
+	m := dynamicpb.NewMessage(md)
+	m.Set(md.Fields().ByName("user"), protoreflect.ValueOfString("u1"))
+	m.Set(md.Fields().ByName("password"), protoreflect.ValueOfString("p1"))
+
+	out := DefaultRedactPolicy().Value(m)
+	require.True(t, strings.Contains(out, `"password":"***"`), out)
+	require.Contains(t, out, `"user":"u1"`)
+	// the message logged is not changed.
+	require.Equal(t, "p1", m.Get(md.Fields().ByName("password")).String())
+}
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000"
+
+// RotateOption configures the rotation of the log file, the file is rotated when either MaxSize or Interval
+// is reached.
+type RotateOption struct {
+	// MaxSize is the size in bytes to rotate the file at, 0 disables it.
+	MaxSize int64 `json:"maxSize"`
+	// Interval is the age to rotate the file at, like 24h for a daily rotation, 0 disables it.
+	Interval time.Duration `json:"interval"`
+	// MaxBackups is the count of the rotated files retained, 0 retains all.
+	MaxBackups int `json:"maxBackups"`
+	// MaxAge removes the rotated files older than it, 0 retains all.
+	MaxAge time.Duration `json:"maxAge"`
+}
+
+// RotateWriter is an io.WriteCloser of a file rotated by RotateOption, the rotated files are named like
+// 'app-20060102T150405.000.log' for 'app.log'.
+type RotateWriter struct {
+	mu       sync.Mutex
+	filename string
+	opt      RotateOption
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+}
+
+func NewRotateWriter(filename string, opt RotateOption) (*RotateWriter, error) {
+	w := &RotateWriter{
+		filename: filename,
+		opt:      opt,
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotateWriter) open() error {
+	f, err := os.OpenFile(w.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	return nil
+}
+
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotateWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.opt.MaxSize > 0 && w.size+n > w.opt.MaxSize {
+		return true
+	}
+	return w.opt.Interval > 0 && time.Since(w.openedAt) >= w.opt.Interval
+}
+
+// Rotate moves the current file to a backup and starts a new one.
+func (w *RotateWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+func (w *RotateWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(w.filename, w.backupName(time.Now())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.cleanup()
+	return nil
+}
+
+func (w *RotateWriter) backupName(t time.Time) string {
+	ext := filepath.Ext(w.filename)
+	prefix := strings.TrimSuffix(w.filename, ext)
+	name := fmt.Sprintf("%s-%s%s", prefix, t.Format(backupTimeFormat), ext)
+	// the files rotated in the same millisecond are not overwritten.
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%s.%d%s", prefix, t.Format(backupTimeFormat), i, ext)
+	}
+}
+
+// backups returns the rotated files, the newest first.
+func (w *RotateWriter) backups() []string {
+	ext := filepath.Ext(w.filename)
+	prefix := strings.TrimSuffix(w.filename, ext)
+	matches, err := filepath.Glob(prefix + "-*" + ext)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(matches))
+	for _, v := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(v, prefix+"-"), ext)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)]); err != nil {
+			continue
+		}
+		result = append(result, v)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(result)))
+	return result
+}
+
+// cleanup removes the backups beyond the retention of the option.
+func (w *RotateWriter) cleanup() {
+	if w.opt.MaxBackups <= 0 && w.opt.MaxAge <= 0 {
+		return
+	}
+
+	for i, v := range w.backups() {
+		remove := w.opt.MaxBackups > 0 && i >= w.opt.MaxBackups
+		if !remove && w.opt.MaxAge > 0 {
+			if info, err := os.Stat(v); err == nil && time.Since(info.ModTime()) > w.opt.MaxAge {
+				remove = true
+			}
+		}
+		if remove {
+			os.Remove(v)
+		}
+	}
+}
+
+// Close closes the file, the writes after it fail with os.ErrClosed. It is safe to be called more than once.
+func (w *RotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.file.Close()
+}
@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSamplingInterval = time.Second
+	defaultSamplingFirst    = 100
+)
+
+// SamplingSummaryMessage is the message of the summary entries of SamplingLogger.
+const SamplingSummaryMessage = "log entries suppressed by sampling"
+
+type SamplingOption struct {
+	// Interval is the period the counts are reset and the summaries are logged at, 1s by default.
+	Interval time.Duration
+	// First is the count of the identical entries logged in an interval, 100 by default.
+	First int
+	// Thereafter logs 1 in Thereafter of the identical entries after First, 0 drops them all.
+	Thereafter int
+	// Keys are the keys of LogEntry.Extra identifying the entries with the message, like logger.FieldMethod.
+	Keys []string
+}
+
+type samplingCounter struct {
+	entry      *LogEntry
+	count      int
+	suppressed int
+}
+
+// SamplingLogger is the Logger sampling the repetitive Debug and Info entries of next, the warnings and errors
+// are logged always. The entries suppressed are counted by a summary entry of each interval.
+type SamplingLogger struct {
+	next Logger
+	opt  SamplingOption
+
+	mu       sync.Mutex
+	counters map[string]*samplingCounter
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewSamplingLogger wraps next, Close should be called to stop the summaries.
+func NewSamplingLogger(next Logger, opt SamplingOption) *SamplingLogger {
+	if opt.Interval <= 0 {
+		opt.Interval = defaultSamplingInterval
+	}
+	if opt.First <= 0 {
+		opt.First = defaultSamplingFirst
+	}
+
+	result := &SamplingLogger{
+		next:     next,
+		opt:      opt,
+		counters: make(map[string]*samplingCounter),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go result.run()
+	return result
+}
+
+func (s *SamplingLogger) key(level Level, entry *LogEntry) string {
+	var sb strings.Builder
+	sb.WriteString(level.String())
+	sb.WriteByte('|')
+	sb.WriteString(entry.Message)
+	for _, k := range s.opt.Keys {
+		sb.WriteByte('|')
+		if v, ok := entry.Extra[k]; ok {
+			sb.WriteString(fmt.Sprint(v))
+		}
+	}
+	return sb.String()
+}
+
+func (s *SamplingLogger) sample(level Level, entry *LogEntry) bool {
+	key := s.key(level, entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok {
+		c = &samplingCounter{entry: entry}
+		s.counters[key] = c
+	}
+	c.count++
+
+	if c.count <= s.opt.First {
+		return true
+	}
+	if s.opt.Thereafter > 0 && (c.count-s.opt.First)%s.opt.Thereafter == 0 {
+		return true
+	}
+	c.suppressed++
+	return false
+}
+
+func (s *SamplingLogger) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.summarize()
+		case <-s.stop:
+			s.summarize()
+			return
+		}
+	}
+}
+
+// summarize resets the counters and logs the summaries of the entries suppressed.
+func (s *SamplingLogger) summarize() {
+	s.mu.Lock()
+	counters := s.counters
+	s.counters = make(map[string]*samplingCounter, len(counters))
+	s.mu.Unlock()
+
+	for _, c := range counters {
+		if c.suppressed == 0 {
+			continue
+		}
+		summary := &LogEntry{
+			Message: SamplingSummaryMessage,
+			Extra: map[string]interface{}{
+				"sampledMessage": c.entry.Message,
+				"suppressed":     c.suppressed,
+				"interval":       s.opt.Interval.String(),
+			},
+		}
+		for _, k := range s.opt.Keys {
+			if v, ok := c.entry.Extra[k]; ok {
+				summary.Extra[k] = v
+			}
+		}
+		s.next.Info(summary)
+	}
+}
+
+func (s *SamplingLogger) SetLevel(level Level) {
+	s.next.SetLevel(level)
+}
+
+func (s *SamplingLogger) GetLevel() Level {
+	return s.next.GetLevel()
+}
+
+func (s *SamplingLogger) Debug(entry *LogEntry) {
+	if s.sample(LevelDebug, entry) {
+		s.next.Debug(entry)
+	}
+}
+
+func (s *SamplingLogger) Info(entry *LogEntry) {
+	if s.sample(LevelInfo, entry) {
+		s.next.Info(entry)
+	}
+}
+
+func (s *SamplingLogger) Warn(entry *LogEntry) {
+	s.next.Warn(entry)
+}
+
+func (s *SamplingLogger) Error(entry *LogEntry) {
+	s.next.Error(entry)
+}
+
+func (s *SamplingLogger) Fatal(entry *LogEntry) {
+	s.next.Fatal(entry)
+}
+
+// Close logs the last summaries and closes next.
+func (s *SamplingLogger) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.closeErr = s.next.Close()
+	})
+	return s.closeErr
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sl, err := NewSlogLogger(SlogOption{Output: buf})
+	require.NoError(t, err)
+
+	s := NewSamplingLogger(sl, SamplingOption{Interval: time.Hour, First: 2, Thereafter: 3, Keys: []string{FieldMethod}})
+	for i := 0; i < 10; i++ {
+		s.Info(NewEntry().WithMessage("success").WithExtra(FieldMethod, "/pkg.Svc/Get"))
+	}
+	s.Info(NewEntry().WithMessage("success").WithExtra(FieldMethod, "/pkg.Svc/Put"))
+	for i := 0; i < 5; i++ {
+		s.Error(NewEntry().WithMessage("failed").WithExtra(FieldMethod, "/pkg.Svc/Get"))
+	}
+	require.NoError(t, s.Close())
+
+	var get, put, failed int
+	var summary map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		switch {
+		case record["msg"] == SamplingSummaryMessage:
+			summary = record
+		case record["msg"] == "failed":
+			failed++
+		case record[FieldMethod] == "/pkg.Svc/Get":
+			get++
+		default:
+			put++
+		}
+	}
+
+	// the first 2, then the 5th and the 8th after them.
+	require.Equal(t, 4, get)
+	require.Equal(t, 1, put)
+	require.Equal(t, 5, failed)
+	require.NotNil(t, summary)
+	require.Equal(t, float64(6), summary["suppressed"])
+	require.Equal(t, "/pkg.Svc/Get", summary[FieldMethod])
+}
@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// slogLevelFatal is above slog.LevelError, it is printed as 'FATAL'.
+const slogLevelFatal = slog.Level(12)
+
+// SlogOption configures NewSlogLogger, the levels are set by SetLevel and SetNamedLevel of the package.
+type SlogOption struct {
+	// Format of the output, FormatJSON or FormatText, FormatJSON by default.
+	Format string `json:"format"`
+	// File is the path of the log file rotated by Rotate, Output is used when empty.
+	File   string       `json:"file"`
+	Rotate RotateOption `json:"rotate"`
+	// Output is the writer when File is empty, os.Stderr by default.
+	Output io.Writer `json:"-"`
+	// AddSource logs the file and line of the caller.
+	AddSource bool `json:"addSource"`
+}
+
+// SlogLogger is the Logger on a slog.Handler, the ids of the entry and its Extra are logged as fields.
+type SlogLogger struct {
+	handler slog.Handler
+	level   *slog.LevelVar
+	off     bool
+	// addSource takes the caller of the entries for slog.HandlerOptions.AddSource.
+	addSource bool
+	mu        sync.RWMutex
+	closer    io.Closer
+	once      sync.Once
+}
+
+// NewSlogLogger returns the Logger writing JSON or text into the output or the rotated file of opt.
+func NewSlogLogger(opt SlogOption) (*SlogLogger, error) {
+	var out io.Writer = os.Stderr
+	var closer io.Closer
+	if opt.File != "" {
+		w, err := NewRotateWriter(opt.File, opt.Rotate)
+		if err != nil {
+			return nil, err
+		}
+		out, closer = w, w
+	} else if opt.Output != nil {
+		out = opt.Output
+	}
+
+	result := &SlogLogger{
+		level:     new(slog.LevelVar),
+		closer:    closer,
+		addSource: opt.AddSource,
+	}
+
+	handlerOpt := &slog.HandlerOptions{
+		AddSource: opt.AddSource,
+		Level:     result.level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				if lv, ok := a.Value.Any().(slog.Level); ok && lv >= slogLevelFatal {
+					a.Value = slog.StringValue(LevelFatal.String())
+				}
+			}
+			return a
+		},
+	}
+
+	switch opt.Format {
+	case FormatJSON, "":
+		result.handler = slog.NewJSONHandler(out, handlerOpt)
+	case FormatText:
+		result.handler = slog.NewTextHandler(out, handlerOpt)
+	default:
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, fmt.Errorf("unknown log format[%s]", opt.Format)
+	}
+
+	result.SetLevel(LevelInfo)
+
+	return result, nil
+}
+
+// pkgPrefix is the prefix of the names of the funcs of the package.
+var pkgPrefix = reflect.TypeOf(LogEntry{}).PkgPath() + "."
+
+// callerPC returns the pc of the first caller outside the package, past its funcs, NamedLogger and the wrapping
+// Loggers like SamplingLogger. It is 0 when every frame is in the package, like for the entries of the worker
+// goroutines.
+func callerPC() uintptr {
+	var pcs [32]uintptr
+	// the frames of runtime.Callers and callerPC are skipped.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for i := 0; ; i++ {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) || strings.HasSuffix(f.File, "_test.go") {
+			if i < n {
+				return pcs[i]
+			}
+			return 0
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+func toSlogLevel(level Level) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slogLevelFatal
+	}
+}
+
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	case level < slogLevelFatal:
+		return LevelError
+	default:
+		return LevelFatal
+	}
+}
+
+func (s *SlogLogger) SetLevel(level Level) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.off = level >= LevelOff
+	s.level.Set(toSlogLevel(level))
+}
+
+func (s *SlogLogger) GetLevel() Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.off {
+		return LevelOff
+	}
+	return fromSlogLevel(s.level.Level())
+}
+
+func (s *SlogLogger) log(level slog.Level, entry *LogEntry) {
+	s.mu.RLock()
+	off := s.off
+	s.mu.RUnlock()
+
+	ctx := context.Background()
+	if off || !s.handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pc uintptr
+	if s.addSource {
+		pc = entry.pc
+		if pc == 0 {
+			pc = callerPC()
+		}
+	}
+	r := slog.NewRecord(time.Now(), level, entry.Message, pc)
+	if entry.TraceId != "" {
+		r.AddAttrs(slog.String("traceId", entry.TraceId))
+	}
+	if entry.SpanId != "" {
+		r.AddAttrs(slog.String("spanId", entry.SpanId))
+	}
+	if entry.ParentId != "" {
+		r.AddAttrs(slog.String("parentId", entry.ParentId))
+	}
+
+	keys := make([]string, 0, len(entry.Extra))
+	for k := range entry.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.AddAttrs(slog.Any(k, entry.Extra[k]))
+	}
+
+	s.handler.Handle(ctx, r)
+}
+
+func (s *SlogLogger) Debug(entry *LogEntry) {
+	s.log(slog.LevelDebug, entry)
+}
+
+func (s *SlogLogger) Info(entry *LogEntry) {
+	s.log(slog.LevelInfo, entry)
+}
+
+func (s *SlogLogger) Warn(entry *LogEntry) {
+	s.log(slog.LevelWarn, entry)
+}
+
+func (s *SlogLogger) Error(entry *LogEntry) {
+	s.log(slog.LevelError, entry)
+}
+
+func (s *SlogLogger) Fatal(entry *LogEntry) {
+	s.log(slogLevelFatal, entry)
+}
+
+// Close closes the rotated file, the entries logged after it are dropped. It is safe to be called more than once.
+func (s *SlogLogger) Close() error {
+	var err error
+	s.once.Do(func() {
+		if s.closer != nil {
+			err = s.closer.Close()
+		}
+	})
+	return err
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+)
+
+// slogHandler is the slog.Handler forwarding the records into the global Logger.
+type slogHandler struct {
+	attrs []slog.Attr
+	group string
+}
+
+// NewSlogHandler returns the slog.Handler forwarding the records into the Logger set by SetLogger, the trace ids
+// are read from the context of the record and the attributes are set into LogEntry.Extra, like
+// 'group.key' for the grouped ones.
+//
+// To route the logs of the libraries using slog and the std log through gsv:
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler()))
+//
+// The Logger should not be built by NewHandlerLogger on slog.Default().Handler() then, nor write through the std
+// log, which would loop.
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	lv := GetLevel()
+	return lv != LevelOff && fromSlogLevel(level) >= lv
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	entry := NewEntry(ctx).WithMessage(r.Message)
+	entry.pc = r.PC
+	for _, a := range h.attrs {
+		setAttr(entry.Extra, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		setAttr(entry.Extra, h.group, a)
+		return true
+	})
+
+	switch fromSlogLevel(r.Level) {
+	case LevelDebug:
+		Debug(entry)
+	case LevelInfo:
+		Info(entry)
+	case LevelWarn:
+		Warn(entry)
+	case LevelError:
+		Error(entry)
+	default:
+		Fatal(entry)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	result := &slogHandler{
+		attrs: make([]slog.Attr, 0, len(h.attrs)+len(attrs)),
+		group: h.group,
+	}
+	result.attrs = append(result.attrs, h.attrs...)
+	for _, a := range attrs {
+		// the attrs are qualified by the group now, as the group may be changed after.
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		result.attrs = append(result.attrs, a)
+	}
+	return result
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	result := &slogHandler{
+		attrs: h.attrs,
+		group: name,
+	}
+	if h.group != "" {
+		result.group = h.group + "." + name
+	}
+	return result
+}
+
+func setAttr(extra map[string]interface{}, group string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := a.Key
+	if group != "" && key != "" {
+		key = group + "." + key
+	} else if key == "" {
+		key = group
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		for _, v := range a.Value.Group() {
+			setAttr(extra, key, v)
+		}
+		return
+	}
+	extra[key] = a.Value.Any()
+}
+
+// NewStdLogger returns the std log.Logger forwarding into the global Logger at level, for the libraries
+// accepting a *log.Logger.
+func NewStdLogger(level Level) *log.Logger {
+	return slog.NewLogLogger(NewSlogHandler(), toSlogLevel(level))
+}
+
+// NewHandlerLogger adapts h as a Logger, the entries are logged by h with the ids and Extra as attributes.
+func NewHandlerLogger(h slog.Handler) *SlogLogger {
+	result := &SlogLogger{
+		handler: h,
+		level:   new(slog.LevelVar),
+	}
+	result.SetLevel(LevelInfo)
+	return result
+}
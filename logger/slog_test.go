@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ringbrew/gsv/internal/gsvctx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l, err := NewSlogLogger(SlogOption{Output: buf})
+	require.NoError(t, err)
+
+	entry := NewEntry().WithMessage("paid").WithExtra("orderId", 1)
+	entry.TraceId = "t1"
+	entry.SpanId = "s1"
+	l.Debug(entry)
+	l.Info(entry)
+	l.Fatal(entry)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "paid", record["msg"])
+	require.Equal(t, "t1", record["traceId"])
+	require.Equal(t, "s1", record["spanId"])
+	require.Equal(t, float64(1), record["orderId"])
+	require.NotContains(t, record, "parentId")
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	require.Equal(t, "FATAL", record["level"])
+
+	l.SetLevel(LevelOff)
+	require.Equal(t, LevelOff, l.GetLevel())
+	l.Fatal(entry)
+	require.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 2)
+
+	_, err = NewSlogLogger(SlogOption{Format: "xml"})
+	require.Error(t, err)
+}
+
+func TestRotateWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	l, err := NewSlogLogger(SlogOption{
+		Format: FormatText,
+		File:   file,
+		Rotate: RotateOption{MaxSize: 200, MaxBackups: 2},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		l.Info(NewEntry().WithMessageF("message %d", i))
+	}
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(file), "app-*.log"))
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	require.LessOrEqual(t, info.Size(), int64(200))
+
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close())
+	// the entries after Close are dropped.
+	l.Info(NewEntry().WithMessage("closed"))
+}
+
+type testCtx struct {
+	gsvctx.Context
+}
+
+func (testCtx) TraceId() string  { return "t1" }
+func (testCtx) SpanId() string   { return "s1" }
+func (testCtx) ParentId() string { return "" }
+
+func TestSlogBridge(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l, err := NewSlogLogger(SlogOption{Output: buf})
+	require.NoError(t, err)
+
+	SetLogger(l)
+	defer SetLogger(NewDefaultLogger())
+
+	// a library logging by slog is forwarded into the Logger with the trace ids of ctx.
+	ctx := gsvctx.NewContext(context.Background(), testCtx{})
+	sl := slog.New(NewSlogHandler()).With("lib", "redis").WithGroup("conn")
+	sl.DebugContext(ctx, "dropped")
+	sl.InfoContext(ctx, "connected", "addr", "localhost:6379")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "connected", record["msg"])
+	require.Equal(t, "t1", record["traceId"])
+	require.Equal(t, "redis", record["lib"])
+	require.Equal(t, "localhost:6379", record["conn.addr"])
+
+	buf.Reset()
+	NewStdLogger(LevelWarn).Print("std message")
+	require.Contains(t, buf.String(), `"level":"WARN"`)
+	require.Contains(t, buf.String(), "std message")
+
+	// any slog.Handler as a Logger.
+	out := &bytes.Buffer{}
+	hl := NewHandlerLogger(slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	hl.Info(NewEntry().WithMessage("adapted").WithExtra("k", "v"))
+	require.Contains(t, out.String(), "msg=adapted k=v")
+}
+
+func TestSlogBridgeDefault(t *testing.T) {
+	prev, prevOut, prevFlags := slog.Default(), log.Writer(), log.Flags()
+
+	SetLogger(NewDefaultLogger())
+	slog.SetDefault(slog.New(NewSlogHandler()))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Info(NewEntry().WithMessage("default logger"))
+		log.Print("std log")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		// the std log stays locked by the logging blocked, it can not be restored.
+		t.Fatal("logging through the slog default does not return")
+	}
+
+	slog.SetDefault(prev)
+	log.SetOutput(prevOut)
+	log.SetFlags(prevFlags)
+}
+
+// syncBuffer is the buffer written by the worker of AsyncLogger.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func TestSlogLoggerAddSource(t *testing.T) {
+	buf := &syncBuffer{}
+	sl, err := NewSlogLogger(SlogOption{Output: buf, AddSource: true})
+	require.NoError(t, err)
+	async := NewAsyncLogger(NewSamplingLogger(sl, SamplingOption{}), AsyncOption{})
+	defer async.Close()
+	defer SetLogger(NewDefaultLogger())
+
+	// the source is the caller past the package funcs and the wrapping Loggers.
+	for _, v := range []Logger{sl, NewSamplingLogger(sl, SamplingOption{}), async} {
+		SetLogger(v)
+		_, _, line, _ := runtime.Caller(0)
+		Info(NewEntry().WithMessage("pkg"))
+		Named("test").Info(NewEntry().WithMessage("named"))
+		require.Eventually(t, func() bool { return strings.Count(buf.String(), "\n") == 2 }, time.Second, time.Millisecond)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		for i, v := range lines {
+			var record struct {
+				Source struct {
+					File string `json:"file"`
+					Line int    `json:"line"`
+				} `json:"source"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(v), &record))
+			require.Equal(t, "slog_test.go", filepath.Base(record.Source.File))
+			require.Equal(t, line+1+i, record.Source.Line)
+		}
+		buf.Reset()
+	}
+}
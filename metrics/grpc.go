@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"sync"
+	"time"
+)
+
+func (m *Metrics) observeServer(fullMethod string, startTime time.Time, err error) {
+	svc, method := splitMethod(fullMethod)
+	code := status.Code(err).String()
+	m.grpcServerRequests.WithLabelValues(svc, method, code).Inc()
+	m.grpcServerDuration.WithLabelValues(svc, method, code).Observe(time.Since(startTime).Seconds())
+}
+
+func (m *Metrics) observeClient(fullMethod string, startTime time.Time, err error) {
+	svc, method := splitMethod(fullMethod)
+	code := status.Code(err).String()
+	m.grpcClientRequests.WithLabelValues(svc, method, code).Inc()
+	m.grpcClientDuration.WithLabelValues(svc, method, code).Observe(time.Since(startTime).Seconds())
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		startTime := time.Now()
+
+		inFlight := m.grpcServerInFlight.WithLabelValues(splitMethod(info.FullMethod))
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		resp, err := handler(ctx, req)
+		m.observeServer(info.FullMethod, startTime, err)
+		return resp, err
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		startTime := time.Now()
+
+		inFlight := m.grpcServerInFlight.WithLabelValues(splitMethod(info.FullMethod))
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		err := handler(srv, ss)
+		m.observeServer(info.FullMethod, startTime, err)
+		return err
+	}
+}
+
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		startTime := time.Now()
+
+		inFlight := m.grpcClientInFlight.WithLabelValues(splitMethod(method))
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.observeClient(method, startTime, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor records a stream once it ends, by an error or io.EOF from RecvMsg, an error of Header
+// or CloseSend, or ctx done for the streams abandoned.
+func (m *Metrics) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		startTime := time.Now()
+
+		inFlight := m.grpcClientInFlight.WithLabelValues(splitMethod(method))
+		inFlight.Inc()
+
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			inFlight.Dec()
+			m.observeClient(method, startTime, err)
+			return s, err
+		}
+
+		result := &clientStream{
+			ClientStream: s,
+			desc:         desc,
+			finished:     make(chan struct{}),
+			finish: func(err error) {
+				m.observeClient(method, startTime, err)
+				inFlight.Dec()
+			},
+		}
+		go func() {
+			select {
+			case <-ctx.Done():
+				result.done(status.FromContextError(ctx.Err()).Err())
+			case <-result.finished:
+			}
+		}()
+		return result, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	desc     *grpc.StreamDesc
+	finish   func(err error)
+	once     sync.Once
+	finished chan struct{}
+}
+
+func (s *clientStream) RecvMsg(msg interface{}) error {
+	err := s.ClientStream.RecvMsg(msg)
+	switch {
+	case err == io.EOF:
+		s.done(nil)
+	case err != nil:
+		s.done(err)
+	case !s.desc.ServerStreams:
+		s.done(nil)
+	}
+	return err
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.done(err)
+	}
+	return md, err
+}
+
+func (s *clientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) done(err error) {
+	s.once.Do(func() {
+		close(s.finished)
+		s.finish(err)
+	})
+}
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return Default().UnaryServerInterceptor()
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return Default().StreamServerInterceptor()
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return Default().UnaryClientInterceptor()
+}
+
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return Default().StreamClientInterceptor()
+}
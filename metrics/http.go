@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"github.com/ringbrew/gsv/service"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const HttpMetricsKey = "HttpMetrics"
+
+// routeUnmatched is the route label of the requests no route matched, the raw path is not used to keep
+// the cardinality bounded.
+const routeUnmatched = "unmatched"
+
+// methodOther is the method label of the requests of the methods not in httpMethods, for the cardinality too.
+const methodOther = "other"
+
+var httpMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+func methodLabel(method string) string {
+	if httpMethods[method] {
+		return method
+	}
+	return methodOther
+}
+
+// HttpMetrics is the server.Handler recording the RED metrics of http requests, labelled by the route template.
+type HttpMetrics struct {
+	Name    string
+	metrics *Metrics
+}
+
+// NewHttpMetrics returns the middleware recording into m, or into Default when m is not given.
+func NewHttpMetrics(m ...*Metrics) *HttpMetrics {
+	result := &HttpMetrics{metrics: Default()}
+	if len(m) > 0 && m[0] != nil {
+		result.metrics = m[0]
+	}
+	return result
+}
+
+func (hm *HttpMetrics) SetName(name string) {
+	hm.Name = name
+}
+
+func (hm *HttpMetrics) GetKey() string {
+	return HttpMetricsKey
+}
+
+func (hm *HttpMetrics) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	startTime := time.Now()
+	method := methodLabel(r.Method)
+
+	inFlight := hm.metrics.httpInFlight.WithLabelValues(hm.Name, method)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	ctx, rec := service.NewRouteRecorderContext(r.Context())
+	next(rw, r.WithContext(ctx))
+
+	route := routeUnmatched
+	if v, ok := rec.Route(); ok {
+		route = v.Path
+	}
+
+	status := http.StatusOK
+	if res, ok := rw.(interface{ Status() int }); ok && res.Status() != 0 {
+		status = res.Status()
+	}
+
+	code := strconv.Itoa(status)
+	hm.metrics.httpRequests.WithLabelValues(hm.Name, method, route, code).Inc()
+	hm.metrics.httpDuration.WithLabelValues(hm.Name, method, route, code).Observe(time.Since(startTime).Seconds())
+}
+
+type HttpMetricsOption = func(m *HttpMetrics)
+
+func WithHttpMetricsName(name string) HttpMetricsOption {
+	return func(m *HttpMetrics) {
+		m.SetName(name)
+	}
+}
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultNamespace = "gsv"
+	DefaultPath      = "/metrics"
+)
+
+// Metrics holds the RED collectors of the http and grpc servers and the grpc clients.
+type Metrics struct {
+	gatherer prometheus.Gatherer
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight *prometheus.GaugeVec
+
+	grpcServerRequests *prometheus.CounterVec
+	grpcServerDuration *prometheus.HistogramVec
+	grpcServerInFlight *prometheus.GaugeVec
+
+	grpcClientRequests *prometheus.CounterVec
+	grpcClientDuration *prometheus.HistogramVec
+	grpcClientInFlight *prometheus.GaugeVec
+}
+
+type Option struct {
+	// Namespace prefixes the metric names, 'gsv' by default.
+	Namespace string
+	// Buckets of the latency histograms in seconds, prometheus.DefBuckets by default.
+	Buckets []float64
+	// Registry is the registry the collectors are registered into and gathered from, prometheus.DefaultRegisterer
+	// and prometheus.DefaultGatherer are used when nil.
+	Registry *prometheus.Registry
+}
+
+var (
+	defaultMetrics *Metrics
+	defaultOnce    sync.Once
+)
+
+// Default returns the Metrics registered into the prometheus default registry, it is used by the
+// middleware and interceptors of this package.
+func Default() *Metrics {
+	defaultOnce.Do(func() {
+		defaultMetrics = New(Option{})
+	})
+	return defaultMetrics
+}
+
+// New creates and registers the collectors, it panics if they are registered already, like prometheus.MustRegister.
+func New(opt Option) *Metrics {
+	if opt.Namespace == "" {
+		opt.Namespace = DefaultNamespace
+	}
+	if len(opt.Buckets) == 0 {
+		opt.Buckets = prometheus.DefBuckets
+	}
+
+	var registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	var gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+	if opt.Registry != nil {
+		registerer, gatherer = opt.Registry, opt.Registry
+	}
+
+	counter := func(subsystem string, name string, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: opt.Namespace, Subsystem: subsystem, Name: name, Help: help}, labels)
+	}
+	histogram := func(subsystem string, name string, help string, labels ...string) *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: opt.Namespace, Subsystem: subsystem, Name: name, Help: help, Buckets: opt.Buckets}, labels)
+	}
+	gauge := func(subsystem string, name string, help string, labels ...string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: opt.Namespace, Subsystem: subsystem, Name: name, Help: help}, labels)
+	}
+
+	m := &Metrics{
+		gatherer: gatherer,
+
+		httpRequests: counter("http", "requests_total", "Total number of http requests handled.", "service", "method", "route", "code"),
+		httpDuration: histogram("http", "request_duration_seconds", "Latency of http requests.", "service", "method", "route", "code"),
+		httpInFlight: gauge("http", "requests_in_flight", "Number of http requests being handled.", "service", "method"),
+
+		grpcServerRequests: counter("grpc_server", "handled_total", "Total number of rpcs handled by the server.", "service", "method", "code"),
+		grpcServerDuration: histogram("grpc_server", "handling_seconds", "Latency of rpcs handled by the server.", "service", "method", "code"),
+		grpcServerInFlight: gauge("grpc_server", "in_flight", "Number of rpcs being handled by the server.", "service", "method"),
+
+		grpcClientRequests: counter("grpc_client", "handled_total", "Total number of rpcs completed by the client.", "service", "method", "code"),
+		grpcClientDuration: histogram("grpc_client", "handling_seconds", "Latency of rpcs completed by the client.", "service", "method", "code"),
+		grpcClientInFlight: gauge("grpc_client", "in_flight", "Number of rpcs being called by the client.", "service", "method"),
+	}
+
+	registerer.MustRegister(
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.grpcServerRequests, m.grpcServerDuration, m.grpcServerInFlight,
+		m.grpcClientRequests, m.grpcClientDuration, m.grpcClientInFlight,
+	)
+
+	return m
+}
+
+// Handler serves the gathered metrics in the prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{})
+}
+
+// Handler is the Handler of Default.
+func Handler() http.Handler {
+	return Default().Handler()
+}
+
+// splitMethod splits a grpc full method like '/pkg.Service/Method' into the service and the method.
+func splitMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "unknown", name
+}
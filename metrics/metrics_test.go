@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/ringbrew/gsv/service"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHttpMetrics(t *testing.T) {
+	m := New(Option{Registry: prometheus.NewRegistry()})
+	hm := NewHttpMetrics(m)
+	hm.SetName("user")
+
+	route := service.NewHttpRoute(http.MethodGet, "/users/{id}", nil)
+	next := func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/none" {
+			service.RecordRoute(r.Context(), route)
+		}
+	}
+
+	hm.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil), next)
+	hm.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/2", nil), next)
+	hm.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/none", nil), next)
+	hm.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("MADEUP", "/none", nil), next)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("user", http.MethodGet, "/users/{id}", "200")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("user", http.MethodGet, routeUnmatched, "200")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.httpInFlight.WithLabelValues("user", http.MethodGet)))
+	// the unknown methods share a label.
+	require.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("user", methodOther, routeUnmatched, "200")))
+
+	rw := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, DefaultPath, nil))
+	require.Contains(t, rw.Body.String(), `gsv_http_requests_total{code="200",method="GET",route="/users/{id}",service="user"} 2`)
+}
+
+func TestGrpcMetrics(t *testing.T) {
+	m := New(Option{Registry: prometheus.NewRegistry()})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/gsv.User/Get"}
+	_, err := m.UnaryServerInterceptor()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(m.grpcServerRequests.WithLabelValues("gsv.User", "Get", codes.NotFound.String())))
+
+	err = m.UnaryClientInterceptor()(context.Background(), "/gsv.User/Get", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(m.grpcClientRequests.WithLabelValues("gsv.User", "Get", codes.OK.String())))
+}
+
+func TestGrpcMetricsStreamCancel(t *testing.T) {
+	m := New(Option{Registry: prometheus.NewRegistry()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := m.StreamClientInterceptor()(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "/gsv.User/Watch", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(m.grpcClientInFlight.WithLabelValues("gsv.User", "Watch")))
+
+	// the stream abandoned without reading to io.EOF is settled once cancelled.
+	cancel()
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.grpcClientInFlight.WithLabelValues("gsv.User", "Watch")) == 0
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 1.0, testutil.ToFloat64(m.grpcClientRequests.WithLabelValues("gsv.User", "Watch", codes.Canceled.String())))
+}
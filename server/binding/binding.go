@@ -17,6 +17,8 @@ import (
 	"sync"
 )
 
+// Binder binds a request into a struct. When params is nil, the path params carried by the request
+// context are used, see param.NewContext.
 type Binder interface {
 	Name() string
 	Bind(*http.Request, interface{}, param.Params) error
@@ -29,11 +31,20 @@ type Binder interface {
 	BindProtobuf(*http.Request, interface{}) error
 }
 
+// ValidateBinder is the Binder validating a struct bound already, the default Binder implements it by
+// BindConfig.Validator. It is apart from Binder, so the Binders implemented outside are not broken by it.
+type ValidateBinder interface {
+	Binder
+	Validate(interface{}) error
+}
+
 const (
 	queryTag           = "query"
 	headerTag          = "header"
 	formTag            = "form"
 	pathTag            = "path"
+	cookieTag          = "cookie"
+	fileNameTag        = "file_name"
 	defaultValidateTag = "vd"
 )
 
@@ -41,6 +52,8 @@ type decoderInfo struct {
 	decoder inDecoder.Decoder
 }
 
+var _ ValidateBinder = (*defaultBinder)(nil)
+
 var defaultBind = NewDefaultBinder(nil)
 
 func DefaultBinder() Binder {
@@ -87,6 +100,9 @@ func (b *defaultBinder) tagCache(tag string) *sync.Map {
 }
 
 func (b *defaultBinder) bindTag(req *http.Request, v interface{}, params param.Params, tag string) error {
+	if params == nil {
+		params, _ = param.FromContext(req.Context())
+	}
 	rv, typeID := valueAndTypeID(v)
 	if err := checkPointer(rv); err != nil {
 		return err
@@ -99,6 +115,9 @@ func (b *defaultBinder) bindTag(req *http.Request, v interface{}, params param.P
 	if len(tag) == 0 {
 		err := b.preBindBody(req, v)
 		if err != nil {
+			if fe, ok := bodyFieldErrors(err); ok {
+				return fe
+			}
 			return fmt.Errorf("bind body failed, err=%v", err)
 		}
 	}
@@ -110,7 +129,7 @@ func (b *defaultBinder) bindTag(req *http.Request, v interface{}, params param.P
 	if ok {
 		// cached fieldDecoder, fast path
 		decoder := cached.(decoderInfo)
-		return decoder.decoder(input)
+		return toFieldErrors(decoder.decoder(input))
 	}
 
 	decodeConfig := &inDecoder.DecodeConfig{
@@ -128,23 +147,35 @@ func (b *defaultBinder) bindTag(req *http.Request, v interface{}, params param.P
 	}
 
 	cache.Store(typeID, decoderInfo{decoder: decoder})
-	return decoder(input)
+	return toFieldErrors(decoder(input))
 }
 
 func (b *defaultBinder) bindTagWithValidate(req *http.Request, v interface{}, params param.Params, tag string) error {
+	if params == nil {
+		params, _ = param.FromContext(req.Context())
+	}
 	rv, typeID := valueAndTypeID(v)
 	if err := checkPointer(rv); err != nil {
 		return err
 	}
 	rt := dereferPointer(rv)
 	if rt.Kind() != reflect.Struct {
-		return b.bindNonStruct(req, v)
+		if err := b.bindNonStruct(req, v); err != nil {
+			if fe, ok := bodyFieldErrors(err); ok {
+				return fe
+			}
+			return err
+		}
+		return b.Validate(v)
 	}
 
 	input := inDecoder.NewDecodeInput(req, params, rv.Elem())
 
 	err := b.preBindBody(req, v)
 	if err != nil {
+		if fe, ok := bodyFieldErrors(err); ok {
+			return fe
+		}
 		return fmt.Errorf("bind body failed, err=%v", err)
 	}
 	cache := b.tagCache(tag)
@@ -154,9 +185,9 @@ func (b *defaultBinder) bindTagWithValidate(req *http.Request, v interface{}, pa
 		decoder := cached.(decoderInfo)
 		err = decoder.decoder(input)
 		if err != nil {
-			return err
+			return toFieldErrors(err)
 		}
-		return err
+		return b.Validate(v)
 	}
 
 	decodeConfig := &inDecoder.DecodeConfig{
@@ -175,10 +206,10 @@ func (b *defaultBinder) bindTagWithValidate(req *http.Request, v interface{}, pa
 	cache.Store(typeID, decoderInfo{decoder: decoder})
 	err = decoder(input)
 	if err != nil {
-		return err
+		return toFieldErrors(err)
 	}
 
-	return err
+	return b.Validate(v)
 }
 
 func (b *defaultBinder) BindQuery(req *http.Request, v interface{}) error {
@@ -198,7 +229,13 @@ func (b *defaultBinder) BindForm(req *http.Request, v interface{}) error {
 }
 
 func (b *defaultBinder) BindJSON(req *http.Request, v interface{}) error {
-	return b.decodeJSON(req.Body, v)
+	if err := b.decodeJSON(req.Body, v); err != nil {
+		if fe, ok := bodyFieldErrors(err); ok {
+			return fe
+		}
+		return err
+	}
+	return nil
 }
 
 func (b *defaultBinder) decodeJSON(r io.Reader, obj interface{}) error {
@@ -235,6 +272,10 @@ func (b *defaultBinder) BindAndValidate(req *http.Request, v interface{}, params
 	return b.bindTagWithValidate(req, v, params, "")
 }
 
+func (b *defaultBinder) Validate(v interface{}) error {
+	return b.config.Validator.ValidateStruct(v)
+}
+
 func (b *defaultBinder) Bind(req *http.Request, v interface{}, params param.Params) error {
 	return b.bindTag(req, v, params, "")
 }
@@ -45,6 +45,10 @@ type BindConfig struct {
 	// NOTE:
 	// time.Time is registered by default
 	TypeUnmarshalFuncs map[reflect.Type]inDecoder.CustomizeDecodeFunc
+	// Validator is used by BindAndValidate to check the bound struct.
+	// NOTE:
+	// The default is DefaultValidator(), which reads rules from the 'vd' tag.
+	Validator StructValidator
 }
 
 func NewBindConfig() *BindConfig {
@@ -55,6 +59,7 @@ func NewBindConfig() *BindConfig {
 		EnableDecoderUseNumber:             false,
 		EnableDecoderDisallowUnknownFields: false,
 		TypeUnmarshalFuncs:                 make(map[reflect.Type]inDecoder.CustomizeDecodeFunc),
+		Validator:                          DefaultValidator(),
 	}
 }
 
@@ -86,6 +91,10 @@ func (config *BindConfig) MustRegTypeUnmarshal(t reflect.Type, fn func(input *in
 }
 
 func (config *BindConfig) initTypeUnmarshal() {
+	if config.Validator == nil {
+		config.Validator = DefaultValidator()
+	}
+
 	config.MustRegTypeUnmarshal(reflect.TypeOf(time.Time{}), func(input *inDecoder.DecodeInput, text string) (reflect.Value, error) {
 		if text == "" {
 			return reflect.ValueOf(time.Time{}), nil
@@ -96,6 +96,7 @@ const (
 	MIMEApplicationDownload     = "application/x-msdownload"
 	MIMEApplicationJSON         = "application/json"
 	MIMEApplicationJSONUTF8     = "application/json; charset=utf-8"
+	MIMEApplicationProblemJSON  = "application/problem+json"
 	MIMEApplicationXML          = "application/xml"
 	MIMEApplicationXMLUTF8      = "application/xml; charset=utf-8"
 	MIMEApplicationZip          = "application/zip"
@@ -0,0 +1,198 @@
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ringbrew/gsv/server/binding/consts"
+	inDecoder "github.com/ringbrew/gsv/server/binding/internal/decoder"
+	"net/http"
+	"strings"
+)
+
+// Source is the part of the request a field is bound from.
+type Source string
+
+const (
+	SourceQuery  Source = "query"
+	SourceHeader Source = "header"
+	SourcePath   Source = "path"
+	SourceForm   Source = "form"
+	SourceCookie Source = "cookie"
+	SourceBody   Source = "body"
+)
+
+const (
+	RuleRequired = inDecoder.RuleRequired
+	RuleType     = inDecoder.RuleType
+	RuleFile     = inDecoder.RuleFile
+	RuleSyntax   = "syntax"
+)
+
+// FieldError describes why a single field of the request is invalid.
+type FieldError struct {
+	// Field is the json path of the field like 'a.b[0].c', or the parameter key for query/header/path/form/cookie.
+	Field   string      `json:"field"`
+	Source  Source      `json:"source"`
+	Rule    string      `json:"rule"`
+	Param   string      `json:"param,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+	Message string      `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// FieldErrors collects all invalid fields of one binding, it is returned by Bind and BindAndValidate.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msg := make([]string, 0, len(e))
+	for i := range e {
+		msg = append(msg, e[i].Error())
+	}
+	return strings.Join(msg, "; ")
+}
+
+// AsFieldErrors returns the FieldErrors in err's chain, if any.
+func AsFieldErrors(err error) (FieldErrors, bool) {
+	var fe FieldErrors
+	if errors.As(err, &fe) {
+		return fe, true
+	}
+	var single *FieldError
+	if errors.As(err, &single) {
+		return FieldErrors{single}, true
+	}
+	return nil, false
+}
+
+func tagSource(tag string) Source {
+	switch tag {
+	case queryTag:
+		return SourceQuery
+	case headerTag:
+		return SourceHeader
+	case pathTag:
+		return SourcePath
+	case formTag, fileNameTag:
+		return SourceForm
+	case cookieTag:
+		return SourceCookie
+	default:
+		return SourceBody
+	}
+}
+
+// fieldSource returns the source of a field by the first binding tag it has.
+// sourceTags are the binding tags of the sources other than the body, in the order the decoder takes them.
+var sourceTags = []string{pathTag, queryTag, headerTag, cookieTag, formTag, fileNameTag}
+
+func fieldSource(tag func(key string) (string, bool)) Source {
+	for _, key := range sourceTags {
+		if _, ok := tag(key); ok {
+			return tagSource(key)
+		}
+	}
+	return SourceBody
+}
+
+// toFieldErrors converts decoder and body errors into FieldErrors, other errors are returned as they are.
+func toFieldErrors(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var des inDecoder.DecodeErrors
+	if errors.As(err, &des) {
+		result := make(FieldErrors, 0, len(des))
+		for _, de := range des {
+			result = append(result, decodeFieldError(de))
+		}
+		return result
+	}
+
+	var de *inDecoder.DecodeError
+	if errors.As(err, &de) {
+		return FieldErrors{decodeFieldError(de)}
+	}
+
+	return err
+}
+
+func decodeFieldError(de *inDecoder.DecodeError) *FieldError {
+	fe := &FieldError{
+		Field:   de.Field,
+		Source:  tagSource(de.Source),
+		Rule:    de.Rule,
+		Message: de.Error(),
+	}
+	if de.Value != "" {
+		fe.Value = de.Value
+	}
+	return fe
+}
+
+// bodyFieldErrors converts the error of unmarshalling a json request body.
+func bodyFieldErrors(err error) (FieldErrors, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return FieldErrors{{
+			Field:   typeErr.Field,
+			Source:  SourceBody,
+			Rule:    RuleType,
+			Param:   typeErr.Type.String(),
+			Value:   typeErr.Value,
+			Message: err.Error(),
+		}}, true
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return FieldErrors{{
+			Source:  SourceBody,
+			Rule:    RuleSyntax,
+			Message: err.Error(),
+		}}, true
+	}
+
+	return nil, false
+}
+
+// Problem is the body written by RenderError, it follows RFC 7807.
+type Problem struct {
+	Type   string      `json:"type"`
+	Title  string      `json:"title"`
+	Status int         `json:"status"`
+	Detail string      `json:"detail,omitempty"`
+	Errors FieldErrors `json:"errors,omitempty"`
+}
+
+// NewProblem builds a 400 Problem from a binding error.
+func NewProblem(err error) Problem {
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+	}
+	if err == nil {
+		return p
+	}
+	p.Detail = err.Error()
+	if fe, ok := AsFieldErrors(err); ok {
+		p.Errors = fe
+	}
+	return p
+}
+
+// RenderError writes err as a 400 application/problem+json response, it is the default renderer for binding errors.
+func RenderError(rw http.ResponseWriter, r *http.Request, err error) {
+	data, mErr := json.Marshal(NewProblem(err))
+	if mErr != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rw.Header().Set(consts.HeaderContentType, consts.MIMEApplicationProblemJSON)
+	rw.WriteHeader(http.StatusBadRequest)
+	rw.Write(data)
+}
@@ -15,6 +15,7 @@ func (d *baseTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 	var text string
 	var exist bool
 	var defaultValue string
+	var source TagInfo
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			if tagInfo.Key == jsonTag {
@@ -24,7 +25,7 @@ func (d *baseTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' field is a 'required' parameter, but the request body does not have this parameter '%s'", d.fieldName, tagInfo.JSONName)
+					err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request body does not have this parameter '%s'", d.fieldName, tagInfo.JSONName))
 				}
 				if len(tagInfo.Default) != 0 && keyExist(input, tagInfo) {
 					defaultValue = ""
@@ -32,6 +33,7 @@ func (d *baseTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 			}
 			continue
 		}
+		source = tagInfo
 		text, exist = tagInfo.Getter(input, tagInfo.Value)
 		defaultValue = tagInfo.Default
 		if exist {
@@ -39,7 +41,7 @@ func (d *baseTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+			err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 		}
 	}
 	if err != nil {
@@ -65,7 +67,7 @@ func (d *baseTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 		var vv reflect.Value
 		vv, err := stringToValue(t, text, input, d.config)
 		if err != nil {
-			return err
+			return newDecodeError(source, d.fieldName, RuleType, text, err)
 		}
 		field.Set(ReferenceValue(vv, ptrDepth))
 		return nil
@@ -74,7 +76,7 @@ func (d *baseTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 	// Non-pointer elems
 	err = d.decoder.UnmarshalString(text, field, d.config.LooseZeroMode)
 	if err != nil {
-		return fmt.Errorf("unable to decode '%s' as %s: %w", text, d.fieldType.Name(), err)
+		return newDecodeError(source, d.fieldName, RuleType, text, fmt.Errorf("unable to decode '%s' as %s: %w", text, d.fieldType.Name(), err))
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package decoder
 
 import (
+	"errors"
 	"fmt"
 	"github.com/ringbrew/gsv/server/binding/consts"
 	"github.com/ringbrew/gsv/server/binding/param"
@@ -59,7 +60,6 @@ type DecodeConfig struct {
 	DisableStructFieldResolve          bool
 	EnableDecoderUseNumber             bool
 	EnableDecoderDisallowUnknownFields bool
-	ValidateTag                        string
 	TypeUnmarshalFuncs                 map[reflect.Type]CustomizeDecodeFunc
 }
 
@@ -88,13 +88,23 @@ func GetReqDecoder(rt reflect.Type, byTag string, config *DecodeConfig) (Decoder
 	}
 
 	return func(input *DecodeInput) error {
+		var errs DecodeErrors
 		for _, decoder := range decoders {
 			err := decoder.Decode(input)
 			if err != nil {
+				// keep decoding to report all invalid fields at once.
+				var de *DecodeError
+				if errors.As(err, &de) {
+					errs = append(errs, de)
+					continue
+				}
 				return err
 			}
 		}
 
+		if len(errs) > 0 {
+			return errs
+		}
 		return nil
 	}, nil
 }
@@ -0,0 +1,59 @@
+package decoder
+
+import (
+	"strings"
+)
+
+const (
+	RuleRequired = "required"
+	RuleType     = "type"
+	RuleFile     = "file"
+)
+
+// DecodeError reports a field which can not be bound from the request.
+type DecodeError struct {
+	// Field is the json path for body fields, or the parameter key for the other sources.
+	Field string
+	// Source is the tag the value is read by, like 'query', 'header' or 'json'.
+	Source string
+	Rule   string
+	Value  string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors collects all fields failed in one decoding.
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	msg := make([]string, 0, len(e))
+	for i := range e {
+		msg = append(msg, e[i].Error())
+	}
+	return strings.Join(msg, "; ")
+}
+
+func newDecodeError(tagInfo TagInfo, fieldName string, rule string, value string, err error) *DecodeError {
+	field := tagInfo.Value
+	if tagInfo.Key == jsonTag && tagInfo.JSONName != "" {
+		field = tagInfo.JSONName
+	}
+	if field == "" || field == "-" {
+		field = fieldName
+	}
+
+	return &DecodeError{
+		Field:  field,
+		Source: tagInfo.Key,
+		Rule:   rule,
+		Value:  value,
+		Err:    err,
+	}
+}
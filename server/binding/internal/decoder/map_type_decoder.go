@@ -15,6 +15,7 @@ func (d *mapTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 	var text string
 	var exist bool
 	var defaultValue string
+	var source TagInfo
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			if tagInfo.Key == jsonTag {
@@ -23,7 +24,7 @@ func (d *mapTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+					err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 				}
 				if len(tagInfo.Default) != 0 && keyExist(input, tagInfo) {
 					defaultValue = ""
@@ -31,6 +32,7 @@ func (d *mapTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 			}
 			continue
 		}
+		source = tagInfo
 		text, exist = tagInfo.Getter(input, tagInfo.Value)
 		defaultValue = tagInfo.Default
 		if exist {
@@ -38,7 +40,7 @@ func (d *mapTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+			err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 		}
 	}
 	if err != nil {
@@ -63,7 +65,7 @@ func (d *mapTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 		var vv reflect.Value
 		vv, err := stringToValue(t, text, input, d.config)
 		if err != nil {
-			return fmt.Errorf("unable to decode '%s' as %s: %w", text, d.fieldType.Name(), err)
+			return newDecodeError(source, d.fieldName, RuleType, text, fmt.Errorf("unable to decode '%s' as %s: %w", text, d.fieldType.Name(), err))
 		}
 		field.Set(ReferenceValue(vv, ptrDepth))
 		return nil
@@ -71,7 +73,7 @@ func (d *mapTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 
 	err = json.Unmarshal([]byte(text), field.Addr().Interface())
 	if err != nil {
-		return fmt.Errorf("unable to decode '%s' as %s: %w", text, d.fieldType.Name(), err)
+		return newDecodeError(source, d.fieldName, RuleType, text, fmt.Errorf("unable to decode '%s' as %s: %w", text, d.fieldType.Name(), err))
 	}
 
 	return nil
@@ -33,7 +33,7 @@ func (d *fileTypeDecoder) Decode(input *DecodeInput) error {
 	}
 	_, file, err := input.Request.FormFile(fileName)
 	if err != nil {
-		return fmt.Errorf("can not get file '%s', err: %v", fileName, err)
+		return &DecodeError{Field: fileName, Source: formTag, Rule: RuleFile, Err: fmt.Errorf("can not get file '%s', err: %v", fileName, err)}
 	}
 	if field.Kind() == reflect.Ptr {
 		t := field.Type()
@@ -92,12 +92,12 @@ func (d *fileTypeDecoder) fileSliceDecode(input *DecodeInput) error {
 	//}
 	files, exist := mf.File[fileName]
 	if !exist {
-		return fmt.Errorf("the file '%s' is not existed", fileName)
+		return &DecodeError{Field: fileName, Source: formTag, Rule: RuleFile, Err: fmt.Errorf("the file '%s' is not existed", fileName)}
 	}
 
 	if field.Kind() == reflect.Array {
 		if len(files) != field.Len() {
-			return fmt.Errorf("the numbers(%d) of file '%s' does not match the length(%d) of %s", len(files), fileName, field.Len(), field.Type().String())
+			return &DecodeError{Field: fileName, Source: formTag, Rule: RuleFile, Err: fmt.Errorf("the numbers(%d) of file '%s' does not match the length(%d) of %s", len(files), fileName, field.Len(), field.Type().String())}
 		}
 	} else {
 		// slice need creating enough capacity
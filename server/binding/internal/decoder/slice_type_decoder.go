@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"mime/multipart"
 	"reflect"
+	"strings"
 )
 
 type sliceTypeFieldTextDecoder struct {
@@ -18,6 +19,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 	var defaultValue string
 	var bindRawBody bool
 	var isDefault bool
+	var source TagInfo
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			if tagInfo.Key == jsonTag {
@@ -26,7 +28,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+					err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 				}
 				if len(tagInfo.Default) != 0 && keyExist(input, tagInfo) { //
 					defaultValue = ""
@@ -37,6 +39,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 		if tagInfo.Key == rawBodyTag {
 			bindRawBody = true
 		}
+		source = tagInfo
 		texts = tagInfo.SliceGetter(input, tagInfo.Value)
 		defaultValue = tagInfo.Default
 		if len(texts) != 0 {
@@ -44,7 +47,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+			err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 		}
 	}
 	if err != nil {
@@ -76,7 +79,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 
 	if d.isArray {
 		if len(texts) != field.Len() && !isDefault {
-			return fmt.Errorf("%q is not valid value for %s", texts, field.Type().String())
+			return newDecodeError(source, d.fieldName, RuleType, strings.Join(texts, ","), fmt.Errorf("%q is not valid value for %s", texts, field.Type().String()))
 		}
 	} else {
 		// slice need creating enough capacity
@@ -100,7 +103,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 	if isDefault {
 		err = json.Unmarshal([]byte(texts[0]), input.ReqValue.Field(d.index).Addr().Interface())
 		if err != nil {
-			return fmt.Errorf("using '%s' to unmarshal field '%s: %s' failed, %v", texts[0], d.fieldName, d.fieldType.String(), err)
+			return newDecodeError(source, d.fieldName, RuleType, texts[0], fmt.Errorf("using '%s' to unmarshal field '%s: %s' failed, %v", texts[0], d.fieldName, d.fieldType.String(), err))
 		}
 		return nil
 	}
@@ -115,12 +118,12 @@ func (d *sliceTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 	}
 	if err != nil {
 		if !input.ReqValue.Field(d.index).CanAddr() {
-			return err
+			return newDecodeError(source, d.fieldName, RuleType, texts[0], err)
 		}
 		// text[0] can be a complete json content for []Type.
 		err = json.Unmarshal([]byte(texts[0]), input.ReqValue.Field(d.index).Addr().Interface())
 		if err != nil {
-			return fmt.Errorf("using '%s' to unmarshal field '%s: %s' failed, %v", texts[0], d.fieldName, d.fieldType.String(), err)
+			return newDecodeError(source, d.fieldName, RuleType, texts[0], fmt.Errorf("using '%s' to unmarshal field '%s: %s' failed, %v", texts[0], d.fieldName, d.fieldType.String(), err))
 		}
 	} else {
 		input.ReqValue.Field(d.index).Set(ReferenceValue(field, parentPtrDepth))
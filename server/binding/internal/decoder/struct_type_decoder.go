@@ -24,7 +24,7 @@ func (d *structTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+					err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 				}
 				if len(tagInfo.Default) != 0 && keyExist(input, tagInfo) {
 					defaultValue = ""
@@ -39,7 +39,7 @@ func (d *structTypeFieldTextDecoder) Decode(input *DecodeInput) error {
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName)
+			err = newDecodeError(tagInfo, d.fieldName, RuleRequired, "", fmt.Errorf("'%s' field is a 'required' parameter, but the request does not have this parameter", d.fieldName))
 		}
 	}
 	if err != nil {
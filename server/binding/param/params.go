@@ -1,5 +1,7 @@
 package param
 
+import "context"
+
 // Param is a single URL parameter, consisting of a key and a value.
 type Param struct {
 	Key   string
@@ -41,3 +43,16 @@ func NewParams(input ...map[string]string) Params {
 	}
 	return result
 }
+
+type paramsCtxKey struct{}
+
+// NewContext returns a new Context that carries the url path params of the request.
+func NewContext(ctx context.Context, ps Params) context.Context {
+	return context.WithValue(ctx, paramsCtxKey{}, ps)
+}
+
+// FromContext returns the url path params stored in ctx, if any.
+func FromContext(ctx context.Context) (Params, bool) {
+	result, ok := ctx.Value(paramsCtxKey{}).(Params)
+	return result, ok
+}
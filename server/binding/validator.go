@@ -0,0 +1,596 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// StructValidator is the validation engine used by Binder.BindAndValidate.
+type StructValidator interface {
+	// ValidateStruct validates the given struct (or pointer/slice of struct) by its validate tag.
+	ValidateStruct(interface{}) error
+	// ValidateTag returns the struct tag the validator reads rules from.
+	ValidateTag() string
+}
+
+// ValidateFunc is a customized validate rule. field is the value being checked,
+// parent is the struct holding it and param is the text after '=' in the rule.
+type ValidateFunc func(field reflect.Value, parent reflect.Value, param string) bool
+
+// ValidateConfig contains options for the default validator.
+type ValidateConfig struct {
+	// ValidateTag is the struct tag the rules are read from.
+	// NOTE:
+	// The default is "vd".
+	ValidateTag string
+	// ValidateFuncs registers customized validate rules by name.
+	ValidateFuncs map[string]ValidateFunc
+}
+
+func NewValidateConfig() *ValidateConfig {
+	return &ValidateConfig{
+		ValidateTag:   defaultValidateTag,
+		ValidateFuncs: make(map[string]ValidateFunc),
+	}
+}
+
+// RegValidateFunc registers a customized validate rule, it can be used as `vd:"name"` or `vd:"name=param"`.
+func (config *ValidateConfig) RegValidateFunc(name string, fn ValidateFunc) error {
+	if name == "" || fn == nil {
+		return fmt.Errorf("validate func name and func cannot be empty")
+	}
+	if _, exist := builtinRules[name]; exist || name == ruleOmitEmpty || name == ruleDive {
+		return fmt.Errorf("validate func '%s' conflicts with a built-in rule", name)
+	}
+	if config.ValidateFuncs == nil {
+		config.ValidateFuncs = make(map[string]ValidateFunc)
+	}
+	config.ValidateFuncs[name] = fn
+	return nil
+}
+
+// MustRegValidateFunc registers a customized validate rule. It will panic if exist error.
+func (config *ValidateConfig) MustRegValidateFunc(name string, fn ValidateFunc) {
+	if err := config.RegValidateFunc(name, fn); err != nil {
+		panic(err)
+	}
+}
+
+const (
+	ruleRequired  = RuleRequired
+	ruleOmitEmpty = "omitempty"
+	ruleDive      = "dive"
+	ruleLen       = "len"
+	ruleMin       = "min"
+	ruleMax       = "max"
+	ruleEq        = "eq"
+	ruleNe        = "ne"
+	ruleGt        = "gt"
+	ruleGte       = "gte"
+	ruleLt        = "lt"
+	ruleLte       = "lte"
+	ruleOneOf     = "oneof"
+	ruleRegex     = "regex"
+	ruleEqField   = "eqfield"
+	ruleNeField   = "nefield"
+	ruleGtField   = "gtfield"
+	ruleGteField  = "gtefield"
+	ruleLtField   = "ltfield"
+	ruleLteField  = "ltefield"
+)
+
+var builtinRules = map[string]ValidateFunc{
+	ruleRequired: func(field, _ reflect.Value, _ string) bool {
+		return !isEmptyValue(field)
+	},
+	ruleLen:      sizeRule(func(size, param float64) bool { return size == param }),
+	ruleMin:      sizeRule(func(size, param float64) bool { return size >= param }),
+	ruleMax:      sizeRule(func(size, param float64) bool { return size <= param }),
+	ruleGt:       sizeRule(func(size, param float64) bool { return size > param }),
+	ruleGte:      sizeRule(func(size, param float64) bool { return size >= param }),
+	ruleLt:       sizeRule(func(size, param float64) bool { return size < param }),
+	ruleLte:      sizeRule(func(size, param float64) bool { return size <= param }),
+	ruleEq:       equalRule(true),
+	ruleNe:       equalRule(false),
+	ruleOneOf:    oneOfRule,
+	ruleEqField:  fieldRule(func(c int) bool { return c == 0 }),
+	ruleNeField:  fieldRule(func(c int) bool { return c != 0 }),
+	ruleGtField:  fieldRule(func(c int) bool { return c > 0 }),
+	ruleGteField: fieldRule(func(c int) bool { return c >= 0 }),
+	ruleLtField:  fieldRule(func(c int) bool { return c < 0 }),
+	ruleLteField: fieldRule(func(c int) bool { return c <= 0 }),
+	// regex is compiled when the rule is parsed, see parseRule.
+	ruleRegex: nil,
+}
+
+var defaultValidate = NewDefaultValidator(nil)
+
+func DefaultValidator() StructValidator {
+	return defaultValidate
+}
+
+type validateRule struct {
+	name  string
+	param string
+	fn    ValidateFunc
+}
+
+type fieldRules struct {
+	index     int
+	name      string
+	source    Source
+	anonymous bool
+	skip      bool
+	omitEmpty bool
+	rules     []validateRule
+	// rules applied to each element of a slice/array/map field, the ones after 'dive'.
+	diveRules []validateRule
+}
+
+type structRules struct {
+	fields []fieldRules
+}
+
+type defaultValidator struct {
+	tag       string
+	funcs     map[string]ValidateFunc
+	ruleCache sync.Map
+}
+
+func NewDefaultValidator(config *ValidateConfig) StructValidator {
+	if config == nil {
+		config = NewValidateConfig()
+	}
+	tag := config.ValidateTag
+	if tag == "" {
+		tag = defaultValidateTag
+	}
+	funcs := make(map[string]ValidateFunc, len(config.ValidateFuncs))
+	for k, v := range config.ValidateFuncs {
+		funcs[k] = v
+	}
+
+	return &defaultValidator{
+		tag:   tag,
+		funcs: funcs,
+	}
+}
+
+func (v *defaultValidator) ValidateTag() string {
+	return v.tag
+}
+
+func (v *defaultValidator) ValidateStruct(obj interface{}) error {
+	if obj == nil {
+		return nil
+	}
+
+	var errs FieldErrors
+	if err := v.validateValue(reflect.ValueOf(obj), "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v *defaultValidator) validateValue(rv reflect.Value, path string, errs *FieldErrors) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return v.validateStruct(rv, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := v.validateValue(rv.Index(i), fmt.Sprintf("%s[%d]", path, i), errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := v.validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *defaultValidator) validateStruct(rv reflect.Value, path string, errs *FieldErrors) error {
+	sr, err := v.getStructRules(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, fr := range sr.fields {
+		if fr.skip {
+			continue
+		}
+		field := rv.Field(fr.index)
+
+		fieldPath := joinPath(path, fr.name)
+		if fr.anonymous {
+			fieldPath = path
+		}
+
+		if fr.omitEmpty && isEmptyValue(field) {
+			continue
+		}
+
+		v.applyRules(fr.rules, field, rv, fieldPath, fr.source, errs)
+
+		if len(fr.diveRules) > 0 {
+			elem := indirectValue(field)
+			switch elem.Kind() {
+			case reflect.Slice, reflect.Array:
+				for i := 0; i < elem.Len(); i++ {
+					v.applyRules(fr.diveRules, elem.Index(i), rv, fmt.Sprintf("%s[%d]", fieldPath, i), fr.source, errs)
+				}
+			case reflect.Map:
+				iter := elem.MapRange()
+				for iter.Next() {
+					v.applyRules(fr.diveRules, iter.Value(), rv, fmt.Sprintf("%s[%v]", fieldPath, iter.Key().Interface()), fr.source, errs)
+				}
+			}
+		}
+
+		// nested struct, slice and map values are validated by their own rules.
+		if err := v.validateValue(field, fieldPath, errs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *defaultValidator) applyRules(rules []validateRule, field reflect.Value, parent reflect.Value, path string, source Source, errs *FieldErrors) {
+	for _, r := range rules {
+		if r.name != ruleRequired {
+			// a nil pointer has nothing to check, use 'required' to reject it.
+			if (field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface) && field.IsNil() {
+				continue
+			}
+		}
+		if !r.fn(indirectValue(field), parent, r.param) {
+			*errs = append(*errs, &FieldError{
+				Field:   path,
+				Source:  source,
+				Rule:    r.name,
+				Param:   r.param,
+				Value:   valueInterface(field),
+				Message: ruleMessage(path, r),
+			})
+		}
+	}
+}
+
+func (v *defaultValidator) getStructRules(rt reflect.Type) (*structRules, error) {
+	if cached, ok := v.ruleCache.Load(rt); ok {
+		return cached.(*structRules), nil
+	}
+
+	sr := &structRules{}
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			// ignore unexported field
+			continue
+		}
+
+		fr := fieldRules{
+			index:     i,
+			name:      fieldName(sf),
+			source:    fieldSource(sf.Tag.Lookup),
+			anonymous: sf.Anonymous && sf.Tag.Get("json") == "",
+		}
+
+		tagContent := sf.Tag.Get(v.tag)
+		if tagContent == "-" {
+			fr.skip = true
+		} else if tagContent != "" {
+			dive := false
+			for _, item := range splitRules(tagContent) {
+				switch item {
+				case "":
+					continue
+				case ruleOmitEmpty:
+					if !dive {
+						fr.omitEmpty = true
+					}
+					continue
+				case ruleDive:
+					dive = true
+					continue
+				}
+				r, err := v.parseRule(item)
+				if err != nil {
+					return nil, fmt.Errorf("invalid '%s' tag of field '%s.%s': %v", v.tag, rt.String(), sf.Name, err)
+				}
+				if dive {
+					fr.diveRules = append(fr.diveRules, r)
+				} else {
+					fr.rules = append(fr.rules, r)
+				}
+			}
+		}
+
+		sr.fields = append(sr.fields, fr)
+	}
+
+	actual, _ := v.ruleCache.LoadOrStore(rt, sr)
+	return actual.(*structRules), nil
+}
+
+func (v *defaultValidator) parseRule(item string) (validateRule, error) {
+	name, param := head(item, "=")
+	r := validateRule{name: name, param: param}
+
+	if fn, exist := v.funcs[name]; exist {
+		r.fn = fn
+		return r, nil
+	}
+
+	fn, exist := builtinRules[name]
+	if !exist {
+		return r, fmt.Errorf("unknown rule '%s'", name)
+	}
+
+	switch name {
+	case ruleRequired:
+	case ruleRegex:
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return r, err
+		}
+		fn = func(field, _ reflect.Value, _ string) bool {
+			if field.Kind() != reflect.String {
+				return false
+			}
+			return re.MatchString(field.String())
+		}
+	case ruleLen, ruleMin, ruleMax, ruleGt, ruleGte, ruleLt, ruleLte:
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return r, fmt.Errorf("rule '%s' needs a numeric param", name)
+		}
+	default:
+		if param == "" {
+			return r, fmt.Errorf("rule '%s' needs a param", name)
+		}
+	}
+	r.fn = fn
+
+	return r, nil
+}
+
+func ruleMessage(path string, r validateRule) string {
+	if r.param != "" {
+		return fmt.Sprintf("'%s' failed on the '%s=%s' rule", path, r.name, r.param)
+	}
+	return fmt.Sprintf("'%s' failed on the '%s' rule", path, r.name)
+}
+
+// splitRules splits the tag content by ',', a literal comma can be written as '\,'.
+func splitRules(content string) []string {
+	var result []string
+	var sb strings.Builder
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c == '\\' && i+1 < len(content) && content[i+1] == ',' {
+			sb.WriteByte(',')
+			i++
+			continue
+		}
+		if c == ',' {
+			result = append(result, strings.TrimSpace(sb.String()))
+			sb.Reset()
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	result = append(result, strings.TrimSpace(sb.String()))
+	return result
+}
+
+func head(str, sep string) (head, tail string) {
+	idx := strings.Index(str, sep)
+	if idx < 0 {
+		return str, ""
+	}
+	return str[:idx], str[idx+len(sep):]
+}
+
+// fieldName is the name of the field in the errors, by the binding tag sourcing it as the DecodeError of the
+// decoder, like 'id' for `query:"id"`, then by the json tag.
+func fieldName(sf reflect.StructField) string {
+	for _, key := range sourceTags {
+		if tv, ok := sf.Tag.Lookup(key); ok {
+			if name := strings.Split(tv, ",")[0]; name != "" && name != "-" {
+				return name
+			}
+			break
+		}
+	}
+	if jt := sf.Tag.Get("json"); jt != "" {
+		if name := strings.Split(jt, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func indirectValue(rv reflect.Value) reflect.Value {
+	for (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func valueInterface(rv reflect.Value) interface{} {
+	rv = indirectValue(rv)
+	if !rv.IsValid() || !rv.CanInterface() {
+		return nil
+	}
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array, reflect.Chan:
+		return rv.Len() == 0
+	default:
+		return rv.IsZero()
+	}
+}
+
+// sizeOf returns the number of a numeric value or the length of a string/slice/array/map value.
+func sizeOf(rv reflect.Value) (float64, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(rv.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(rv.Len()), true
+	}
+	return 0, false
+}
+
+func sizeRule(cmp func(size, param float64) bool) ValidateFunc {
+	return func(field, _ reflect.Value, param string) bool {
+		size, ok := sizeOf(field)
+		if !ok {
+			return false
+		}
+		p, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false
+		}
+		return cmp(size, p)
+	}
+}
+
+func equalRule(expect bool) ValidateFunc {
+	return func(field, _ reflect.Value, param string) bool {
+		switch field.Kind() {
+		case reflect.String:
+			return (field.String() == param) == expect
+		case reflect.Bool:
+			b, err := strconv.ParseBool(param)
+			if err != nil {
+				return false
+			}
+			return (field.Bool() == b) == expect
+		}
+		size, ok := sizeOf(field)
+		if !ok {
+			return false
+		}
+		p, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false
+		}
+		return (size == p) == expect
+	}
+}
+
+func oneOfRule(field, _ reflect.Value, param string) bool {
+	var text string
+	switch field.Kind() {
+	case reflect.String:
+		text = field.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		text = strconv.FormatInt(field.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		text = strconv.FormatUint(field.Uint(), 10)
+	default:
+		return false
+	}
+	for _, item := range strings.Fields(param) {
+		if item == text {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldRule compares the field with a sibling field named by param.
+func fieldRule(cmp func(c int) bool) ValidateFunc {
+	return func(field, parent reflect.Value, param string) bool {
+		if parent.Kind() != reflect.Struct {
+			return false
+		}
+		other := parent.FieldByName(param)
+		if !other.IsValid() {
+			return false
+		}
+		c, ok := compareValues(field, indirectValue(other))
+		if !ok {
+			return false
+		}
+		return cmp(c)
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func compareValues(a, b reflect.Value) (int, bool) {
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	if a.Kind() == reflect.Bool && b.Kind() == reflect.Bool {
+		if a.Bool() == b.Bool() {
+			return 0, true
+		}
+		return 1, true
+	}
+	x, ok := sizeOf(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := sizeOf(b)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
@@ -0,0 +1,182 @@
+package binding
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testAddress struct {
+	City string `json:"city" vd:"required"`
+	Zip  string `json:"zip" vd:"omitempty,regex=^[0-9]{6}$"`
+}
+
+type testUser struct {
+	Name     string         `json:"name" vd:"required,min=2,max=8"`
+	Age      int            `json:"age" vd:"gte=18,lt=150"`
+	Role     string         `json:"role" vd:"oneof=admin guest"`
+	Password string         `json:"password" vd:"len=6"`
+	Confirm  string         `json:"confirm" vd:"eqfield=Password"`
+	Start    int            `json:"start"`
+	End      int            `json:"end" vd:"gtfield=Start"`
+	Tags     []string       `json:"tags" vd:"max=3,dive,min=1"`
+	Address  *testAddress   `json:"address"`
+	History  []testAddress  `json:"history"`
+	Ignore   *testAddress   `json:"ignore" vd:"-"`
+	Code     string         `json:"code" vd:"even"`
+	Extra    map[string]int `json:"extra" vd:"dive,lte=10"`
+}
+
+func newTestValidator(t *testing.T) StructValidator {
+	config := NewValidateConfig()
+	config.MustRegValidateFunc("even", func(field, _ reflect.Value, _ string) bool {
+		n, err := strconv.Atoi(field.String())
+		return err == nil && n%2 == 0
+	})
+	require.Error(t, config.RegValidateFunc(ruleRequired, func(reflect.Value, reflect.Value, string) bool { return true }))
+	return NewDefaultValidator(config)
+}
+
+func validUser() *testUser {
+	return &testUser{
+		Name:     "gsv",
+		Age:      20,
+		Role:     "admin",
+		Password: "123456",
+		Confirm:  "123456",
+		Start:    1,
+		End:      2,
+		Tags:     []string{"a"},
+		Address:  &testAddress{City: "gz", Zip: "510000"},
+		History:  []testAddress{{City: "sz"}},
+		Ignore:   &testAddress{},
+		Code:     "2",
+		Extra:    map[string]int{"a": 1},
+	}
+}
+
+func TestValidateStruct(t *testing.T) {
+	v := newTestValidator(t)
+	require.NoError(t, v.ValidateStruct(validUser()))
+
+	u := validUser()
+	u.Name = "g"
+	u.Age = 17
+	u.Role = "root"
+	u.Confirm = "654321"
+	u.End = 1
+	u.Tags = []string{"a", ""}
+	u.Address.Zip = "abc"
+	u.History = append(u.History, testAddress{})
+	u.Code = "3"
+	u.Extra["b"] = 11
+
+	err := v.ValidateStruct(u)
+	require.Error(t, err)
+
+	errs, ok := AsFieldErrors(err)
+	require.True(t, ok)
+
+	failed := make(map[string]string)
+	for _, e := range errs {
+		failed[e.Field] = e.Rule
+	}
+
+	require.Equal(t, map[string]string{
+		"name":            ruleMin,
+		"age":             ruleGte,
+		"role":            ruleOneOf,
+		"confirm":         ruleEqField,
+		"end":             ruleGtField,
+		"tags[1]":         ruleMin,
+		"address.zip":     ruleRegex,
+		"history[1].city": ruleRequired,
+		"code":            "even",
+		"extra[b]":        ruleLte,
+	}, failed)
+}
+
+func TestValidateInvalidRule(t *testing.T) {
+	type invalid struct {
+		A string `vd:"unknown"`
+	}
+	require.Error(t, NewDefaultValidator(nil).ValidateStruct(&invalid{}))
+}
+
+func TestBindAndValidate(t *testing.T) {
+	type req struct {
+		Id   int64  `query:"id" vd:"gt=0"`
+		Name string `json:"name" vd:"required"`
+	}
+
+	body := `{"name":""}`
+	r, _ := http.NewRequest(http.MethodPost, "/user?id=0", bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	err := DefaultBinder().BindAndValidate(r, &req{}, nil)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "'id' failed on the 'gt=0' rule"))
+	require.True(t, strings.Contains(err.Error(), "'name' failed on the 'required' rule"))
+
+	// the fields are named the same as the decoder names them.
+	fe, ok := AsFieldErrors(err)
+	require.True(t, ok)
+	require.Equal(t, "id", fe[0].Field)
+	require.Equal(t, SourceQuery, fe[0].Source)
+
+	// the default Binder validates a struct alone.
+	vb, ok := DefaultBinder().(ValidateBinder)
+	require.True(t, ok)
+	require.Error(t, vb.Validate(&req{}))
+	require.NoError(t, vb.Validate(&req{Id: 1, Name: "gsv"}))
+}
+
+func TestBindFieldErrors(t *testing.T) {
+	type req struct {
+		Id    int64  `query:"id"`
+		Token string `header:"X-Token,required"`
+		Age   int    `json:"age"`
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/user?id=abc", nil)
+	err := DefaultBinder().Bind(r, &req{}, nil)
+	fe, ok := AsFieldErrors(err)
+	require.True(t, ok)
+	require.Len(t, fe, 2)
+	require.Equal(t, "id", fe[0].Field)
+	require.Equal(t, SourceQuery, fe[0].Source)
+	require.Equal(t, RuleType, fe[0].Rule)
+	require.Equal(t, "abc", fe[0].Value)
+	require.Equal(t, "X-Token", fe[1].Field)
+	require.Equal(t, SourceHeader, fe[1].Source)
+	require.Equal(t, RuleRequired, fe[1].Rule)
+
+	body := `{"age":"old"}`
+	r, _ = http.NewRequest(http.MethodPost, "/user", bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	r.Header.Set("X-Token", "t")
+	err = DefaultBinder().Bind(r, &req{}, nil)
+	fe, ok = AsFieldErrors(err)
+	require.True(t, ok)
+	require.Equal(t, "age", fe[0].Field)
+	require.Equal(t, SourceBody, fe[0].Source)
+
+	rw := httptest.NewRecorder()
+	RenderError(rw, r, err)
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+	require.Equal(t, "application/problem+json", rw.Header().Get("Content-Type"))
+
+	var p Problem
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &p))
+	require.Len(t, p.Errors, 1)
+	require.Equal(t, "age", p.Errors[0].Field)
+}
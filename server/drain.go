@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/tracex"
+	"google.golang.org/grpc"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultDrainTimeout      = 30 * time.Second
+	defaultTraceFlushTimeout = 5 * time.Second
+)
+
+// DrainOption configures how a server stops. The sequence is: mark not ready, deregister the nodes,
+// wait PropagationDelay, stop accepting, drain in-flight requests until Timeout, stop hard, then flush the traces.
+type DrainOption struct {
+	// PropagationDelay is the wait after deregistering, for the clients and load balancers to see the node leaving.
+	PropagationDelay time.Duration
+	// Timeout is the deadline to drain the in-flight requests, 30s by default.
+	Timeout time.Duration
+	// TraceFlushTimeout is the deadline to flush the spans of tracex after draining, 5s by default.
+	TraceFlushTimeout time.Duration
+}
+
+func (o DrainOption) timeout() time.Duration {
+	if o.Timeout <= 0 {
+		return defaultDrainTimeout
+	}
+	return o.Timeout
+}
+
+// flushTrace exports the spans buffered by tracex, the ones of the drained requests included.
+func (o DrainOption) flushTrace(server string) {
+	timeout := o.TraceFlushTimeout
+	if timeout <= 0 {
+		timeout = defaultTraceFlushTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	logDrain(server, "flush traces")
+	if err := tracex.ForceFlush(ctx); err != nil {
+		serverLog.Warn(logger.NewEntry().WithMessage(fmt.Sprintf("%s drain: flush traces error: %s", server, err.Error())))
+	}
+}
+
+func logDrain(server string, phase string) {
+	serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("%s drain: %s", server, phase)))
+}
+
+// waitPropagation sleeps the propagation delay of the option.
+func (o DrainOption) waitPropagation(server string) {
+	if o.PropagationDelay <= 0 {
+		return
+	}
+	logDrain(server, fmt.Sprintf("wait propagation delay %s", o.PropagationDelay))
+	time.Sleep(o.PropagationDelay)
+}
+
+// drainHttp stops accepting and waits the in-flight requests of hs until ctx is done, the connections left
+// are closed after it.
+func drainHttp(ctx context.Context, server string, hs *http.Server) error {
+	logDrain(server, "stop accepting and drain in-flight requests")
+	if err := hs.Shutdown(ctx); err != nil {
+		serverLog.Warn(logger.NewEntry().WithMessage(fmt.Sprintf("%s drain: deadline exceeded, force close: %s", server, err.Error())))
+		return hs.Close()
+	}
+	logDrain(server, "drained")
+	return nil
+}
+
+// drainGrpc is drainHttp for grpc servers, the server is stopped hard when ctx is done before GracefulStop returns.
+func drainGrpc(ctx context.Context, server string, gs *grpc.Server) {
+	logDrain(server, "stop accepting and drain in-flight requests")
+
+	done := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logDrain(server, "drained")
+	case <-ctx.Done():
+		serverLog.Warn(logger.NewEntry().WithMessage(fmt.Sprintf("%s drain: deadline exceeded, force stop", server)))
+		gs.Stop()
+		<-done
+	}
+}
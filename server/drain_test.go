@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ringbrew/gsv/discovery"
+	"github.com/stretchr/testify/require"
+)
+
+type drainRegister struct {
+	registry            *HealthRegistry
+	servingAtDeregister []bool
+}
+
+func (r *drainRegister) Register(node *discovery.Node) error {
+	return nil
+}
+
+func (r *drainRegister) KeepAlive(node *discovery.Node) error {
+	return nil
+}
+
+func (r *drainRegister) Deregister(node *discovery.Node) error {
+	r.servingAtDeregister = append(r.servingAtDeregister, r.registry.Serving())
+	return nil
+}
+
+func TestGrpcDrain(t *testing.T) {
+	register := &drainRegister{registry: NewHealthRegistry()}
+	s := newGrpcServer(Option{
+		ServerRegister: register,
+		Health:         HealthOption{Enable: true, Registry: register.registry},
+		Drain:          DrainOption{PropagationDelay: 10 * time.Millisecond, Timeout: time.Second},
+	})
+
+	require.NoError(t, s.registerNode(context.Background(), discovery.NewNode("drain", "127.0.0.1", 3000, discovery.GRPC, "")))
+	s.setServing(true)
+
+	start := time.Now()
+	s.drain()
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	require.Equal(t, []bool{false}, register.servingAtDeregister)
+
+	// no node is registered once draining.
+	require.NoError(t, s.registerNode(context.Background(), discovery.NewNode("drain", "127.0.0.1", 3001, discovery.GRPC, "")))
+	require.Empty(t, s.nodes)
+}
+
+func TestDrainHttpDeadline(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	block := make(chan struct{})
+	defer close(block)
+	hs := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-block
+	})}
+	go hs.Serve(lis)
+
+	go http.Get("http://" + lis.Addr().String())
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, drainHttp(ctx, "test", hs))
+}
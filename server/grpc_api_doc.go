@@ -0,0 +1,364 @@
+package server
+
+import (
+	"fmt"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// DocMethodGRPC is the DocApi Method of a gRPC method, its Path is the full method name like '/pkg.Service/Method'.
+	DocMethodGRPC = "GRPC"
+
+	docContentTypeGRPC = "application/grpc"
+)
+
+var (
+	// gatewayPathVar matches the variables of google.api.http templates, like '{name=messages/*}'.
+	gatewayPathVar = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+	protoExampleMarshaller = protojson.MarshalOptions{
+		Multiline:       true,
+		UseEnumNumbers:  true,
+		EmitUnpopulated: true,
+	}
+)
+
+var protoSources = struct {
+	sync.RWMutex
+	files []*protoregistry.Files
+}{}
+
+// RegisterProtoSourceInfo registers the comments of the descriptor set data for the docs of the grpc services,
+// protoc-gen-go strips them from the descriptors it generates. data is the FileDescriptorSet written by
+// 'protoc --include_imports --include_source_info --descriptor_set_out', like embedded by go:embed.
+func RegisterProtoSourceInfo(data []byte) error {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return fmt.Errorf("proto descriptor set unmarshal error: %w", err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return fmt.Errorf("proto descriptor set error: %w", err)
+	}
+
+	protoSources.Lock()
+	protoSources.files = append(protoSources.files, files)
+	protoSources.Unlock()
+	return nil
+}
+
+func (gs *grpcServer) Doc() []DocService {
+	result := make([]DocService, 0, len(gs.serviceList))
+	for i := range gs.serviceList {
+		gds := DocService{
+			Key:  gs.serviceList[i].Name(),
+			Name: gs.serviceList[i].Remark(),
+		}
+
+		if gds.Name == "" {
+			continue
+		}
+
+		desc := gs.serviceList[i].Description()
+		for ii := range desc.GrpcServiceDesc {
+			gds.Api = append(gds.Api, grpcServiceApi(desc.GrpcServiceDesc[ii])...)
+		}
+		result = append(result, gds)
+	}
+
+	return result
+}
+
+// grpcServiceApi walks the descriptor of sd in the global registry, the methods of a service which is not
+// registered there are listed without messages.
+func grpcServiceApi(sd grpc.ServiceDesc) []DocApi {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(sd.ServiceName))
+	if err != nil {
+		return grpcServiceDescApi(sd)
+	}
+	svc, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return grpcServiceDescApi(sd)
+	}
+
+	result := make([]DocApi, 0, svc.Methods().Len())
+	methods := svc.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+
+		api := DocApi{
+			Name:            protoComment(md),
+			Path:            fmt.Sprintf("/%s/%s", sd.ServiceName, md.Name()),
+			Method:          DocMethodGRPC,
+			ContentType:     docContentTypeGRPC,
+			Request:         messageStructs(md.Input()),
+			Response:        messageStructs(md.Output()),
+			RequestExample:  protoExample(md.Input()),
+			ResponseExample: protoExample(md.Output()),
+			Module:          string(svc.Name()),
+			Action:          string(md.Name()),
+			ClientStreaming: md.IsStreamingClient(),
+			ServerStreaming: md.IsStreamingServer(),
+		}
+		if api.Name == "" {
+			api.Name = string(md.Name())
+		}
+		result = append(result, api)
+
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			if ga, ok := gatewayApi(api, md, r); ok {
+				result = append(result, ga)
+			}
+		}
+	}
+
+	return result
+}
+
+func grpcServiceDescApi(sd grpc.ServiceDesc) []DocApi {
+	result := make([]DocApi, 0, len(sd.Methods)+len(sd.Streams))
+	for _, m := range sd.Methods {
+		result = append(result, DocApi{
+			Name:        m.MethodName,
+			Path:        fmt.Sprintf("/%s/%s", sd.ServiceName, m.MethodName),
+			Method:      DocMethodGRPC,
+			ContentType: docContentTypeGRPC,
+			Action:      m.MethodName,
+		})
+	}
+	for _, s := range sd.Streams {
+		result = append(result, DocApi{
+			Name:            s.StreamName,
+			Path:            fmt.Sprintf("/%s/%s", sd.ServiceName, s.StreamName),
+			Method:          DocMethodGRPC,
+			ContentType:     docContentTypeGRPC,
+			Action:          s.StreamName,
+			ClientStreaming: s.ClientStreams,
+			ServerStreaming: s.ServerStreams,
+		})
+	}
+	return result
+}
+
+// gatewayApi returns the api served by the gateway for one google.api.http binding of md.
+func gatewayApi(api DocApi, md protoreflect.MethodDescriptor, rule *annotations.HttpRule) (DocApi, bool) {
+	var method, path string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		method, path = http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		method, path = http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		method, path = http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		method, path = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		method, path = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		method, path = strings.ToUpper(p.Custom.GetKind()), p.Custom.GetPath()
+	}
+	if path == "" {
+		return DocApi{}, false
+	}
+
+	pathVars := make(map[string]struct{})
+	for _, m := range gatewayPathVar.FindAllStringSubmatch(path, -1) {
+		pathVars[m[1]] = struct{}{}
+	}
+
+	api.Path = gatewayPathVar.ReplaceAllString(path, "{$1}")
+	api.Method = method
+	api.ContentType = "application/json"
+	api.ClientStreaming = false
+	api.ServerStreaming = false
+
+	// the fields of the root request are bound by the path, the body or the query.
+	if len(api.Request) > 0 {
+		request := append([]Struct{}, api.Request...)
+		root := request[0]
+		root.Field = append([]Field{}, root.Field...)
+
+		fields := md.Input().Fields()
+		for i := range root.Field {
+			fd := fields.ByJSONName(root.Field[i].Name)
+			if fd == nil {
+				continue
+			}
+			_, inPath := pathVars[string(fd.Name())]
+			switch {
+			case inPath:
+				root.Field[i].In = "path"
+			case rule.GetBody() == "*", rule.GetBody() == string(fd.Name()):
+				// bound by the body.
+			default:
+				root.Field[i].In = "query"
+			}
+		}
+		request[0] = root
+		api.Request = request
+	}
+
+	return api, true
+}
+
+// messageStructs is structInfo for protobuf messages, the fields are named by their json names as the gateway does.
+func messageStructs(input protoreflect.MessageDescriptor) []Struct {
+	result := make([]Struct, 0, 1)
+	set := make(map[protoreflect.FullName]struct{})
+	list := []protoreflect.MessageDescriptor{input}
+
+	for len(list) > 0 {
+		md := list[0]
+		list = list[1:]
+
+		if _, exist := set[md.FullName()]; exist {
+			continue
+		}
+		set[md.FullName()] = struct{}{}
+
+		curr := Struct{
+			Name: string(md.FullName()),
+		}
+
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+
+			ft, embed := protoFieldType(fd)
+			if embed != nil {
+				list = append(list, embed)
+			}
+
+			curr.Field = append(curr.Field, Field{
+				Name:     fd.JSONName(),
+				Type:     ft,
+				Remark:   protoComment(fd),
+				Required: protoRequired(fd),
+			})
+		}
+
+		result = append(result, curr)
+	}
+
+	// mark root.
+	if len(result) > 0 {
+		result[0].Root = true
+	}
+
+	return result
+}
+
+// protoFieldType returns the FieldType of fd, and the message it refers to which should be documented too.
+func protoFieldType(fd protoreflect.FieldDescriptor) (FieldType, protoreflect.MessageDescriptor) {
+	if fd.IsMap() {
+		return FieldType{Category: FieldTypeCategoryObject}, nil
+	}
+
+	ft, embed := protoKindType(fd)
+	if fd.IsList() {
+		return FieldType{Category: FieldTypeCategoryArray, Name: "array", Items: &ft}, embed
+	}
+	return ft, embed
+}
+
+func protoKindType(fd protoreflect.FieldDescriptor) (FieldType, protoreflect.MessageDescriptor) {
+	basic := func(name string) (FieldType, protoreflect.MessageDescriptor) {
+		return FieldType{Category: FieldTypeCategoryBasic, Name: name}, nil
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return basic("bool")
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return basic("int32")
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return basic("uint32")
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return basic("int64")
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return basic("uint64")
+	case protoreflect.FloatKind:
+		return basic("float32")
+	case protoreflect.DoubleKind:
+		return basic("float64")
+	case protoreflect.StringKind:
+		return basic("string")
+	case protoreflect.BytesKind:
+		return basic("bytes")
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := fd.Message()
+		switch md.FullName() {
+		case "google.protobuf.Timestamp":
+			return FieldType{Category: FieldTypeCategoryObject, Name: "Time"}, nil
+		case "google.protobuf.Duration", "google.protobuf.FieldMask":
+			return basic("string")
+		case "google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.Any", "google.protobuf.Empty":
+			return FieldType{Category: FieldTypeCategoryObject}, nil
+		}
+		// the wrappers are marshalled as their value.
+		if md.ParentFile().Package() == "google.protobuf" && strings.HasSuffix(string(md.Name()), "Value") && md.Fields().Len() == 1 {
+			return protoKindType(md.Fields().Get(0))
+		}
+		return FieldType{Category: FieldTypeCategoryObject, Name: string(md.FullName())}, md
+	}
+
+	return basic("")
+}
+
+func protoRequired(fd protoreflect.FieldDescriptor) bool {
+	if fd.Cardinality() == protoreflect.Required {
+		return true
+	}
+	behaviors, _ := proto.GetExtension(fd.Options(), annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	for _, v := range behaviors {
+		if v == annotations.FieldBehavior_REQUIRED {
+			return true
+		}
+	}
+	return false
+}
+
+// protoComment returns the leading comment of d. The descriptors generated by protoc-gen-go carry no source info,
+// so the comment is looked up in the descriptor sets of RegisterProtoSourceInfo then, it is empty if none has d.
+func protoComment(d protoreflect.Descriptor) string {
+	if loc := d.ParentFile().SourceLocations().ByDescriptor(d); loc.LeadingComments != "" {
+		return strings.TrimSpace(loc.LeadingComments)
+	}
+
+	protoSources.RLock()
+	defer protoSources.RUnlock()
+	for _, files := range protoSources.files {
+		sd, err := files.FindDescriptorByName(d.FullName())
+		if err != nil {
+			continue
+		}
+		if loc := sd.ParentFile().SourceLocations().ByDescriptor(sd); loc.LeadingComments != "" {
+			return strings.TrimSpace(loc.LeadingComments)
+		}
+	}
+	return ""
+}
+
+func protoExample(md protoreflect.MessageDescriptor) string {
+	data, err := protoExampleMarshaller.Marshal(dynamicpb.NewMessage(md))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
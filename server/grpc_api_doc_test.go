@@ -0,0 +1,91 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/ringbrew/gsv/service"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type healthDocService struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (s *healthDocService) Name() string {
+	return "health"
+}
+
+func (s *healthDocService) Remark() string {
+	return "health check"
+}
+
+func (s *healthDocService) Description() service.Description {
+	return service.Description{
+		Valid: true,
+		GrpcServiceDesc: []grpc.ServiceDesc{
+			grpc_health_v1.Health_ServiceDesc,
+			{ServiceName: "gsv.Unknown", Methods: []grpc.MethodDesc{{MethodName: "Ping"}}},
+		},
+	}
+}
+
+func TestGrpcDoc(t *testing.T) {
+	s := newGrpcServer(Option{})
+	require.NoError(t, s.Register(&healthDocService{}))
+
+	docs := s.Doc()
+	require.Len(t, docs, 1)
+	require.Equal(t, "health", docs[0].Key)
+
+	apis := make(map[string]DocApi)
+	for _, v := range docs[0].Api {
+		apis[v.Path] = v
+	}
+
+	check := apis["/grpc.health.v1.Health/Check"]
+	require.Equal(t, DocMethodGRPC, check.Method)
+	require.False(t, check.ServerStreaming)
+	require.Equal(t, "grpc.health.v1.HealthCheckRequest", check.Request[0].Name)
+	require.True(t, check.Request[0].Root)
+	require.Equal(t, "service", check.Request[0].Field[0].Name)
+	require.Equal(t, "string", check.Request[0].Field[0].Type.Name)
+	require.Contains(t, check.ResponseExample, "status")
+
+	require.True(t, apis["/grpc.health.v1.Health/Watch"].ServerStreaming)
+
+	ping := apis["/gsv.Unknown/Ping"]
+	require.Equal(t, "Ping", ping.Action)
+	require.Empty(t, ping.Request)
+}
+
+func TestGrpcDocSourceInfo(t *testing.T) {
+	defer func() { protoSources.files = nil }()
+
+	// the descriptor set of 'protoc --include_source_info', the generated descriptors have no comments.
+	fd := protodesc.ToFileDescriptorProto(grpc_health_v1.File_grpc_health_v1_health_proto)
+	fd.SourceCodeInfo = &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+		{Path: []int32{6, 0, 2, 0}, Span: []int32{0, 0, 1}, LeadingComments: proto.String(" Check the health.\n")},
+		{Path: []int32{4, 0, 2, 0}, Span: []int32{0, 0, 1}, LeadingComments: proto.String(" the service name.\n")},
+	}}
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fd}})
+	require.NoError(t, err)
+	require.NoError(t, RegisterProtoSourceInfo(data))
+	require.Error(t, RegisterProtoSourceInfo([]byte("invalid")))
+
+	s := newGrpcServer(Option{})
+	require.NoError(t, s.Register(&healthDocService{}))
+
+	for _, v := range s.Doc()[0].Api {
+		if v.Path == "/grpc.health.v1.Health/Check" {
+			require.Equal(t, "Check the health.", v.Name)
+			require.Equal(t, "the service name.", v.Request[0].Field[0].Remark)
+			return
+		}
+	}
+	t.Fatal("no check api")
+}
@@ -34,6 +34,7 @@ func gatewayTraceMiddleware(h http.Handler) http.Handler {
 		ctx := r.Context()
 		bags, spanCtx := tracex.HttpExtract(ctx, propagation.HeaderCarrier(r.Header))
 		ctx = baggage.ContextWithBaggage(ctx, bags)
+		ctx = tracex.HttpDebug(ctx, propagation.HeaderCarrier(r.Header))
 
 		tracer := tracex.NewConfig().TracerProvider.Tracer(
 			tracex.InstrumentationName,
@@ -92,6 +93,7 @@ func gatewayTraceyInterceptor() grpc.UnaryClientInterceptor {
 
 		sc := span.SpanContext()
 		rpcCtx := tracex.NewServiceContext(sc.TraceID(), sc.SpanID())
+		tracex.CopyBaggage(ctx, rpcCtx)
 		ctx = service.NewContext(ctx, rpcCtx)
 
 		err := invoker(ctx, method, req, reply, cc, callOpts...)
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/service"
 	"github.com/ringbrew/gsv/tracex"
 	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
@@ -43,6 +45,8 @@ func TraceStreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 
 		bags, spanCtx := tracex.GrpcExtract(ctx, &metadataCopy)
 		ctx = baggage.ContextWithBaggage(ctx, bags)
+		ctx = tracex.GrpcDebug(ctx, &metadataCopy)
+		ctx = logger.WithFields(ctx, logger.FieldMethod, info.FullMethod, logger.FieldPeer, tracex.PeerFromCtx(ctx))
 
 		tracer := tracex.NewConfig().TracerProvider.Tracer(
 			tracex.InstrumentationName,
@@ -58,6 +62,11 @@ func TraceStreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 		)
 		defer span.End()
 
+		sc := span.SpanContext()
+		rpcCtx := tracex.NewServiceContext(sc.TraceID(), sc.SpanID())
+		tracex.CopyBaggage(ctx, rpcCtx)
+		ctx = service.NewContext(ctx, rpcCtx)
+
 		err := handler(srv, wrapServerStream(ctx, ss))
 
 		if err != nil {
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/service"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryInterceptorsLogCorrelation(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(prev)
+
+	rec := logger.Record(t)
+
+	trace, log := TraceUnaryInterceptor(), LogUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/gsv.test.Pay/Create"}
+
+	var traceId string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		rpcCtx, ok := service.FromContext(ctx)
+		require.True(t, ok)
+		traceId = rpcCtx.TraceId()
+		logger.Info(logger.NewEntry(ctx).WithMessage("handling"))
+		return nil, errors.New("failed")
+	}
+
+	_, err := trace(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return log(ctx, req, info, handler)
+	})
+	require.Error(t, err)
+
+	require.NotEmpty(t, traceId)
+	entries := rec.Entries().TraceId(traceId)
+	require.Equal(t, []string{"handling", "failed"}, entries.Messages())
+	require.Equal(t, 1, entries.Level(logger.LevelError).Extra(logger.FieldMethod, info.FullMethod).Len())
+	require.Equal(t, "server", entries.Level(logger.LevelError)[0].Extra[logger.NameKey])
+}
@@ -47,9 +47,9 @@ func LogUnaryInterceptor() grpc.UnaryServerInterceptor {
 			WithExtra("code", code)
 
 		if err != nil {
-			logger.Error(entry.WithMessage(err.Error()))
+			serverLog.Error(entry.WithMessage(err.Error()))
 		} else {
-			logger.Info(entry.WithMessage("success"))
+			serverLog.Info(entry.WithMessage("success"))
 		}
 
 		return resp, err
@@ -63,6 +63,8 @@ func TraceUnaryInterceptor() grpc.UnaryServerInterceptor {
 
 		bags, spanCtx := tracex.GrpcExtract(ctx, &metadataCopy)
 		ctx = baggage.ContextWithBaggage(ctx, bags)
+		ctx = tracex.GrpcDebug(ctx, &metadataCopy)
+		ctx = logger.WithFields(ctx, logger.FieldMethod, info.FullMethod, logger.FieldPeer, tracex.PeerFromCtx(ctx))
 
 		tracer := tracex.NewConfig().TracerProvider.Tracer(
 			tracex.InstrumentationName,
@@ -82,6 +84,7 @@ func TraceUnaryInterceptor() grpc.UnaryServerInterceptor {
 
 		sc := span.SpanContext()
 		rpcCtx := tracex.NewServiceContext(sc.TraceID(), sc.SpanID())
+		tracex.CopyBaggage(ctx, rpcCtx)
 		ctx = service.NewContext(ctx, rpcCtx)
 
 		resp, err := handler(ctx, req)
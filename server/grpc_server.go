@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"sync"
@@ -15,7 +14,10 @@ import (
 	"github.com/ringbrew/gsv/logger"
 	"github.com/ringbrew/gsv/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/stats"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -33,11 +35,24 @@ type grpcServer struct {
 	statHandler        stats.Handler
 	register           discovery.NodeRegister
 
-	enableGateway       bool
-	gSrvGateway         *http.Server
-	gatewayMux          *runtime.ServeMux
-	gatewayShutdownDone chan struct{} // 用于通知 gateway 已关闭完成
-	serviceList         []service.Service
+	enableGateway bool
+	gSrvGateway   *http.Server
+	gatewayMux    *runtime.ServeMux
+	serviceList   []service.Service
+
+	drainOpt DrainOption
+	nodeMu   sync.Mutex
+	nodes    []*discovery.Node
+	draining bool
+
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	tls    *tlsReloader
+	tlsErr error
+
+	health    *healthHandler
+	healthSrv *health.Server
 
 	WaitGroup sync.WaitGroup
 }
@@ -55,6 +70,8 @@ func newGrpcServer(opt Option) *grpcServer {
 		statHandler:        opt.StatHandler,
 		register:           opt.ServerRegister,
 		enableGateway:      opt.EnableGrpcGateway,
+		drainOpt:           opt.Drain,
+		ready:              make(chan struct{}),
 	}
 
 	if s.host == "" {
@@ -75,8 +92,23 @@ func newGrpcServer(opt Option) *grpcServer {
 		opts = append(opts, grpc.StatsHandler(opt.StatHandler))
 	}
 
+	if opt.CertFile != "" && opt.KeyFile != "" {
+		// the error is returned by Run.
+		s.tls, s.tlsErr = newTLSReloader(opt.CertFile, opt.KeyFile, opt.TLS)
+		if s.tls != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(s.tls.ServerConfig())))
+		}
+	}
+
 	s.gSrv = grpc.NewServer(opts...)
 
+	if opt.Health.Enable {
+		s.health = newHealthHandler(opt.Health)
+		s.healthSrv = health.NewServer()
+		s.healthSrv.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		grpc_health_v1.RegisterHealthServer(s.gSrv, s.healthSrv)
+	}
+
 	if s.enableGateway {
 		m := runtime.NewServeMux(runtime.WithMarshalerOption("*", &runtime.JSONPb{
 			MarshalOptions: protojson.MarshalOptions{
@@ -86,6 +118,16 @@ func newGrpcServer(opt Option) *grpcServer {
 		}))
 		httpMux := http.NewServeMux()
 		httpMux.Handle("/", m)
+		if s.health != nil {
+			httpMux.HandleFunc(s.health.opt.LivePath, s.health.ServeLive)
+			httpMux.HandleFunc(s.health.opt.ReadyPath, s.health.ServeReady)
+		}
+		if opt.Metrics.Enable {
+			httpMux.Handle(opt.Metrics.path(), opt.Metrics.handler())
+		}
+		if opt.LogLevel.Enable {
+			httpMux.Handle(opt.LogLevel.path(), logger.LevelHandler())
+		}
 
 		hs := &http.Server{
 			Addr:    fmt.Sprintf(":%d", s.proxyPort),
@@ -94,7 +136,6 @@ func newGrpcServer(opt Option) *grpcServer {
 
 		s.gatewayMux = m
 		s.gSrvGateway = hs
-		s.gatewayShutdownDone = make(chan struct{})
 	}
 
 	return s
@@ -112,39 +153,80 @@ func (gs *grpcServer) Register(srv service.Service) error {
 
 	gs.serviceList = append(gs.serviceList, srv)
 
+	if checker, ok := srv.(HealthChecker); ok && gs.health != nil {
+		gs.health.registry().Register(srv.Name(), checker)
+	}
+
 	return nil
 }
 
-func (gs *grpcServer) Run(ctx context.Context) {
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithChainUnaryInterceptor(gatewayTraceyInterceptor())}
+// Run binds the listeners, registers the services and the nodes, then serves until ctx is done or serving fails.
+// The errors of binding, registering and serving are returned, Ready is closed once the node is serving.
+func (gs *grpcServer) Run(ctx context.Context) error {
+	if gs.tlsErr != nil {
+		return gs.tlsErr
+	}
 
-	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", gs.port), opts...)
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", gs.port))
 	if err != nil {
-		log.Fatal(err.Error())
+		return fmt.Errorf("rpc server listen error: %w", err)
 	}
 
-	for i := range gs.serviceList {
-		desc := gs.serviceList[i].Description()
-
-		for _, v := range desc.GrpcServiceDesc {
-			gs.gSrv.RegisterService(&v, gs.serviceList[i])
+	var gatewayLis net.Listener
+	if gs.enableGateway {
+		gatewayLis, err = net.Listen("tcp", fmt.Sprintf(":%d", gs.proxyPort))
+		if err != nil {
+			lis.Close()
+			return fmt.Errorf("rpc server gateway listen error: %w", err)
 		}
+	}
 
-		for _, f := range desc.GrpcGateway {
-			if err := f(context.Background(), gs.gatewayMux, conn); err != nil {
-				log.Fatal(err.Error())
-			}
+	conn, err := gs.registerServices(lis.Addr().(*net.TCPAddr).Port)
+	if err != nil {
+		lis.Close()
+		if gatewayLis != nil {
+			gatewayLis.Close()
 		}
+		return err
 	}
+	defer conn.Close()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	gs.WaitGroup = sync.WaitGroup{}
+	errCh := make(chan error, 2)
 
-	if gs.enableGateway {
+	if gs.health != nil {
+		services := make([]string, 0, len(gs.serviceList))
+		for i := range gs.serviceList {
+			for _, v := range gs.serviceList[i].Description().GrpcServiceDesc {
+				services = append(services, v.ServiceName)
+			}
+		}
+
+		gs.WaitGroup.Add(1)
+		go func() {
+			defer gs.WaitGroup.Done()
+			gs.health.watch(runCtx, gs.healthSrv, services)
+		}()
+	}
+
+	if gatewayLis != nil {
 		gs.WaitGroup.Add(1)
 		go func() {
 			defer gs.WaitGroup.Done()
-			if err := gs.runGateway(ctx); err != nil {
-				log.Fatal(fmt.Errorf("server gateway run error:%s", err.Error()))
+			var err error
+			if gs.tls != nil {
+				serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server gateway start listen tls on: [%d]", gs.proxyPort)))
+				gs.gSrvGateway.TLSConfig = gs.tls.ServerConfig()
+				err = gs.gSrvGateway.ServeTLS(gatewayLis, "", "")
+			} else {
+				serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server gateway start listen on: [%d]", gs.proxyPort)))
+				err = gs.gSrvGateway.Serve(gatewayLis)
+			}
+			if err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("server gateway run error:%w", err)
 			}
 		}()
 	}
@@ -152,76 +234,99 @@ func (gs *grpcServer) Run(ctx context.Context) {
 	gs.WaitGroup.Add(1)
 	go func() {
 		defer gs.WaitGroup.Done()
-		if err := gs.run(ctx); err != nil {
-			log.Fatal(fmt.Errorf("server run error:%s", err.Error()))
+		serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server start listen on: [%d]", gs.port)))
+		if err := gs.gSrv.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+			errCh <- fmt.Errorf("server run error:%w", err)
 		}
 	}()
 
+	if err = gs.registerNodes(runCtx); err == nil {
+		gs.setServing(true)
+		gs.readyOnce.Do(func() {
+			close(gs.ready)
+		})
+
+		select {
+		case <-ctx.Done():
+		case err = <-errCh:
+		}
+	}
+
+	gs.drain()
+	cancel()
 	gs.WaitGroup.Wait()
+
+	return err
 }
 
-func (gs *grpcServer) registerNode(ctx context.Context, node *discovery.Node) error {
-	if gs.register == nil || node == nil {
-		return nil
+// Ready is closed once the listeners are bound and the node is serving.
+func (gs *grpcServer) Ready() <-chan struct{} {
+	return gs.ready
+}
+
+// registerServices registers the services into the grpc server, and the gateways with a loopback connection to port.
+func (gs *grpcServer) registerServices(port int) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if gs.tls != nil {
+		creds = credentials.NewTLS(gs.tls.LoopbackConfig())
 	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds), grpc.WithChainUnaryInterceptor(gatewayTraceyInterceptor())}
 
-	gs.WaitGroup.Add(1)
-	go func() {
-		defer gs.WaitGroup.Done()
-		<-ctx.Done()
-		if err := gs.register.Deregister(node); err != nil {
-			logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("node[%s]-[%s]-[%d] deregister error %s", node.Name, node.Host, node.Port, err.Error())))
-		} else {
-			logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("node[%s]-[%s]-[%d] success deregister", node.Name, node.Host, node.Port)))
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("rpc server dial error: %w", err)
+	}
+
+	for i := range gs.serviceList {
+		desc := gs.serviceList[i].Description()
+
+		for _, v := range desc.GrpcServiceDesc {
+			gs.gSrv.RegisterService(&v, gs.serviceList[i])
 		}
-	}()
 
-	if err := gs.register.Register(node); err != nil {
-		return err
-	}
+		if !gs.enableGateway {
+			continue
+		}
 
-	go func() {
-		defer func() {
-			if p := recover(); p != nil {
-				logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server[%s] keep alive panic:%v", gs.name, p)))
+		for _, f := range desc.GrpcGateway {
+			if err := f(context.Background(), gs.gatewayMux, conn); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("rpc server gateway register error: %w", err)
 			}
-		}()
-		if err := gs.register.KeepAlive(node); err != nil {
-			logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server[%s] keep alive error:%v", gs.name, err.Error())))
 		}
-	}()
+	}
 
-	return nil
+	return conn, nil
 }
 
-func (gs *grpcServer) run(ctx context.Context) error {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", gs.port))
-	if err != nil {
-		return err
+func (gs *grpcServer) registerNodes(ctx context.Context) error {
+	if gs.register == nil || gs.name == "" {
+		return nil
 	}
 
-	gs.WaitGroup.Add(1)
-	go func() {
-		defer gs.WaitGroup.Done()
-		<-ctx.Done()
-		// 如果启用了 gateway，等待 gateway 先关闭完成
-		if gs.enableGateway && gs.gatewayShutdownDone != nil {
-			<-gs.gatewayShutdownDone
+	if gs.host != "" {
+		node := discovery.NewNode(gs.name, gs.host, gs.port, discovery.GRPC, gs.nodeId)
+		if err := gs.registerNode(ctx, node); err != nil {
+			return err
 		}
-		gs.gSrv.GracefulStop()
-		logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server stop listen on: [%d]", gs.port)))
-	}()
+	}
 
-	if gs.register != nil && gs.name != "" {
-		if gs.host != "" {
-			node := discovery.NewNode(gs.name, gs.host, gs.port, discovery.GRPC, gs.nodeId)
-			if err := gs.registerNode(ctx, node); err != nil {
-				return err
-			}
+	for _, v := range gs.external {
+		node := discovery.NewNode(gs.name, v, gs.port, discovery.GRPC, gs.nodeId)
+		node.WithTag(TagExternal)
+		if err := gs.registerNode(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	if gs.enableGateway && gs.host != "" {
+		node := discovery.NewNode(gs.name, gs.host, gs.proxyPort, discovery.HTTP, gs.nodeId)
+		if err := gs.registerNode(ctx, node); err != nil {
+			return err
 		}
 
 		for _, v := range gs.external {
-			node := discovery.NewNode(gs.name, v, gs.port, discovery.GRPC, gs.nodeId)
+			node := discovery.NewNode(gs.name, v, gs.proxyPort, discovery.HTTP, gs.nodeId)
 			node.WithTag(TagExternal)
 			if err := gs.registerNode(ctx, node); err != nil {
 				return err
@@ -229,62 +334,99 @@ func (gs *grpcServer) run(ctx context.Context) error {
 		}
 	}
 
-	logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server start listen on: [%d]", gs.port)))
+	return nil
+}
 
-	if err := gs.gSrv.Serve(lis); err != nil {
-		return err
+func (gs *grpcServer) registerNode(ctx context.Context, node *discovery.Node) error {
+	if gs.register == nil || node == nil {
+		return nil
 	}
 
-	return nil
-}
+	gs.nodeMu.Lock()
+	defer gs.nodeMu.Unlock()
 
-func (gs *grpcServer) runGateway(ctx context.Context) error {
-	if gs.gSrvGateway == nil {
-		logger.Fatal(logger.NewEntry().WithMessage("gateway is nil"))
+	// the node is deregistered by drain, it should not be registered once draining.
+	if gs.draining {
+		return nil
 	}
 
-	gs.WaitGroup.Add(1)
+	if err := gs.register.Register(node); err != nil {
+		return err
+	}
+	gs.nodes = append(gs.nodes, node)
+
 	go func() {
-		defer gs.WaitGroup.Done()
-		<-ctx.Done()
-		if err := gs.gSrvGateway.Shutdown(context.Background()); err != nil {
-			logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("failed to shutdown http server: %s", err.Error())))
+		defer func() {
+			if p := recover(); p != nil {
+				serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server[%s] keep alive panic:%v", gs.name, p)))
+			}
+		}()
+		if err := gs.register.KeepAlive(node); err != nil {
+			serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server[%s] keep alive error:%v", gs.name, err.Error())))
 		}
-		logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server gateway stop listen on: [%d]", gs.proxyPort)))
-		// 通知 gRPC server 可以开始关闭了
-		close(gs.gatewayShutdownDone)
 	}()
 
-	if gs.register != nil && gs.name != "" && gs.host != "" {
-		if gs.host != "" {
-			node := discovery.NewNode(gs.name, gs.host, gs.proxyPort, discovery.HTTP, gs.nodeId)
-			if err := gs.registerNode(ctx, node); err != nil {
-				return err
-			}
+	return nil
+}
+
+// drain stops the server by the sequence of DrainOption, the gateway is drained before the grpc server it proxies to.
+func (gs *grpcServer) drain() {
+	logDrain("rpc server", "mark not ready")
+	gs.setServing(false)
+
+	gs.nodeMu.Lock()
+	gs.draining = true
+	nodes := gs.nodes
+	gs.nodes = nil
+	gs.nodeMu.Unlock()
+
+	if len(nodes) > 0 {
+		logDrain("rpc server", "deregister nodes")
+	}
+	for _, node := range nodes {
+		if err := gs.register.Deregister(node); err != nil {
+			serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("node[%s]-[%s]-[%d] deregister error %s", node.Name, node.Host, node.Port, err.Error())))
+		} else {
+			serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("node[%s]-[%s]-[%d] success deregister", node.Name, node.Host, node.Port)))
 		}
+	}
 
-		for _, v := range gs.external {
-			node := discovery.NewNode(gs.name, v, gs.proxyPort, discovery.HTTP, gs.nodeId)
-			node.WithTag(TagExternal)
-			if err := gs.registerNode(ctx, node); err != nil {
-				return err
-			}
+	gs.drainOpt.waitPropagation("rpc server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), gs.drainOpt.timeout())
+	defer cancel()
+
+	if gs.gSrvGateway != nil {
+		if err := drainHttp(ctx, "rpc server gateway", gs.gSrvGateway); err != nil {
+			serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("failed to shutdown http server: %s", err.Error())))
 		}
+		serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server gateway stop listen on: [%d]", gs.proxyPort)))
 	}
 
-	logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server gateway start listen on: [%d]", gs.proxyPort)))
+	drainGrpc(ctx, "rpc server", gs.gSrv)
+	serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("rpc server stop listen on: [%d]", gs.port)))
 
-	if err := gs.gSrvGateway.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return err
+	gs.drainOpt.flushTrace("rpc server")
+}
+
+// setServing flips the readiness of the node, the grpc.health.v1 status can not be serving again once stopped.
+func (gs *grpcServer) setServing(serving bool) {
+	if gs.health == nil {
+		return
 	}
 
-	return nil
+	gs.health.registry().SetServing(serving)
+	if serving {
+		gs.health.refresh(context.Background(), gs.healthSrv, nil)
+	} else {
+		gs.healthSrv.Shutdown()
+	}
 }
 
 func (gs *grpcServer) findListenOn() string {
 	conn, err := net.Dial("udp", "8.8.8.8:80")
 	if err != nil {
-		logger.Warn(logger.NewEntry().WithMessage(fmt.Sprintf("failed to get server host, msg[%v]", err.Error())))
+		serverLog.Warn(logger.NewEntry().WithMessage(fmt.Sprintf("failed to get server host, msg[%v]", err.Error())))
 		return ""
 	}
 	defer conn.Close()
@@ -293,7 +435,3 @@ func (gs *grpcServer) findListenOn() string {
 
 	return localAddr.IP.String()
 }
-
-func (gs *grpcServer) Doc() []DocService {
-	return nil
-}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/server/binding/consts"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultLivePath       = "/healthz"
+	defaultReadyPath      = "/readyz"
+	defaultHealthTimeout  = 3 * time.Second
+	defaultHealthInterval = 5 * time.Second
+)
+
+const (
+	HealthServing    = "SERVING"
+	HealthNotServing = "NOT_SERVING"
+)
+
+// HealthChecker checks a dependency of the node, like a db ping. A service implementing it is registered
+// into the HealthRegistry of the server by its name.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+type HealthCheckFunc func(ctx context.Context) error
+
+func (f HealthCheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthRegistry holds the checkers and the serving state of a node, the node is ready when it is serving
+// and all the checkers pass.
+type HealthRegistry struct {
+	mu       sync.RWMutex
+	checkers map[string]HealthChecker
+	serving  atomic.Bool
+}
+
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		checkers: make(map[string]HealthChecker),
+	}
+}
+
+// Register adds a checker, the checker registered before by the same name is replaced.
+func (r *HealthRegistry) Register(name string, checker HealthChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers[name] = checker
+}
+
+func (r *HealthRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checkers, name)
+}
+
+func (r *HealthRegistry) SetServing(serving bool) {
+	r.serving.Store(serving)
+}
+
+func (r *HealthRegistry) Serving() bool {
+	return r.serving.Load()
+}
+
+// Check runs all the checkers concurrently and returns the failed ones by name.
+func (r *HealthRegistry) Check(ctx context.Context) map[string]error {
+	r.mu.RLock()
+	checkers := make(map[string]HealthChecker, len(r.checkers))
+	for k, v := range r.checkers {
+		checkers[k] = v
+	}
+	r.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	result := make(map[string]error)
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func(name string, checker HealthChecker) {
+			defer wg.Done()
+			defer func() {
+				if p := recover(); p != nil {
+					mu.Lock()
+					result[name] = fmt.Errorf("health checker panic: %v", p)
+					mu.Unlock()
+				}
+			}()
+			if err := checker.Check(ctx); err != nil {
+				mu.Lock()
+				result[name] = err
+				mu.Unlock()
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// Ready reports whether the node is serving and all the checkers pass.
+func (r *HealthRegistry) Ready(ctx context.Context) bool {
+	return r.Serving() && len(r.Check(ctx)) == 0
+}
+
+type HealthOption struct {
+	Enable bool
+	// Registry is shared by the servers of one node, it is created when nil.
+	Registry *HealthRegistry
+	// LivePath is '/healthz' by default.
+	LivePath string
+	// ReadyPath is '/readyz' by default.
+	ReadyPath string
+	// Timeout limits one round of the checkers, 3s by default.
+	Timeout time.Duration
+	// Interval is the period the grpc.health.v1 status is refreshed in, 5s by default.
+	Interval time.Duration
+}
+
+type healthResult struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type healthHandler struct {
+	opt HealthOption
+}
+
+func newHealthHandler(opt HealthOption) *healthHandler {
+	if opt.Registry == nil {
+		opt.Registry = NewHealthRegistry()
+	}
+	if opt.LivePath == "" {
+		opt.LivePath = defaultLivePath
+	}
+	if opt.ReadyPath == "" {
+		opt.ReadyPath = defaultReadyPath
+	}
+	if opt.Timeout <= 0 {
+		opt.Timeout = defaultHealthTimeout
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = defaultHealthInterval
+	}
+	return &healthHandler{opt: opt}
+}
+
+func (h *healthHandler) registry() *HealthRegistry {
+	return h.opt.Registry
+}
+
+// ServeLive answers the liveness probe, the node is alive as long as it can respond.
+func (h *healthHandler) ServeLive(rw http.ResponseWriter, r *http.Request) {
+	h.write(rw, http.StatusOK, healthResult{Status: HealthServing})
+}
+
+// ServeReady answers the readiness probe, it fails while the node is starting, draining or any checker fails.
+func (h *healthHandler) ServeReady(rw http.ResponseWriter, r *http.Request) {
+	if !h.registry().Serving() {
+		h.write(rw, http.StatusServiceUnavailable, healthResult{Status: HealthNotServing})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.opt.Timeout)
+	defer cancel()
+
+	failed := h.registry().Check(ctx)
+	if len(failed) == 0 {
+		h.write(rw, http.StatusOK, healthResult{Status: HealthServing})
+		return
+	}
+
+	result := healthResult{
+		Status: HealthNotServing,
+		Checks: make(map[string]string, len(failed)),
+	}
+	for name, err := range failed {
+		result.Checks[name] = err.Error()
+	}
+	h.write(rw, http.StatusServiceUnavailable, result)
+}
+
+func (h *healthHandler) write(rw http.ResponseWriter, code int, result healthResult) {
+	data, _ := json.Marshal(result)
+	rw.Header().Set(consts.HeaderContentType, consts.MIMEApplicationJSON)
+	rw.WriteHeader(code)
+	rw.Write(data)
+}
+
+// watch refreshes the status of the grpc.health.v1 server until ctx is done, the overall status is
+// set for the empty service name.
+func (h *healthHandler) watch(ctx context.Context, hs *health.Server, services []string) {
+	ticker := time.NewTicker(h.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.refresh(ctx, hs, services)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *healthHandler) refresh(ctx context.Context, hs *health.Server, services []string) {
+	checkCtx, cancel := context.WithTimeout(ctx, h.opt.Timeout)
+	defer cancel()
+
+	status := grpc_health_v1.HealthCheckResponse_SERVING
+	if !h.registry().Serving() {
+		status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	} else if failed := h.registry().Check(checkCtx); len(failed) > 0 {
+		names := make([]string, 0, len(failed))
+		for name := range failed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		serverLog.Warn(logger.NewEntry().WithMessage(fmt.Sprintf("health check failed: %v", names)))
+		status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+
+	hs.SetServingStatus("", status)
+	for _, v := range services {
+		hs.SetServingStatus(v, status)
+	}
+}
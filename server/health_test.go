@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHttpHealth(t *testing.T) {
+	registry := NewHealthRegistry()
+	s := newHttpServer(Option{Health: HealthOption{Enable: true, Registry: registry}})
+	s.registerHealth()
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		s.router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+		return rw
+	}
+
+	require.Equal(t, http.StatusOK, serve(defaultLivePath).Code)
+	require.Equal(t, http.StatusServiceUnavailable, serve(defaultReadyPath).Code)
+
+	registry.SetServing(true)
+	require.Equal(t, http.StatusOK, serve(defaultReadyPath).Code)
+
+	registry.Register("db", HealthCheckFunc(func(ctx context.Context) error {
+		return errors.New("db down")
+	}))
+	rw := serve(defaultReadyPath)
+	require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	require.Contains(t, rw.Body.String(), "db down")
+
+	registry.Unregister("db")
+	require.Equal(t, http.StatusOK, serve(defaultReadyPath).Code)
+}
+
+func TestGrpcHealth(t *testing.T) {
+	s := newGrpcServer(Option{Health: HealthOption{Enable: true}})
+	require.NoError(t, s.Register(&healthDocService{}))
+
+	check := func() grpc_health_v1.HealthCheckResponse_ServingStatus {
+		resp, err := s.healthSrv.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		return resp.Status
+	}
+
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check())
+	s.setServing(true)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check())
+	s.setServing(false)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check())
+	require.False(t, s.health.registry().Serving())
+}
@@ -22,12 +22,17 @@ type DocApi struct {
 	ResponseExample string
 	Module          string
 	Action          string
+	// ClientStreaming and ServerStreaming are the streaming flags of gRPC methods.
+	ClientStreaming bool
+	ServerStreaming bool
 }
 
 type Struct struct {
-	Name  string
-	Field []Field
-	Root  bool
+	Name string
+	// PkgPath is the package of the Go struct, which tells the structs of the same Name apart.
+	PkgPath string
+	Field   []Field
+	Root    bool
 }
 
 type Field struct {
@@ -36,12 +41,18 @@ type Field struct {
 	Remark    string
 	Required  bool
 	Anonymous bool
+	// In is the binding tag of the field, like 'path', 'query' or 'header', empty for the body.
+	In string
 }
 
+var fieldInTags = []string{"path", "query", "header", "cookie", "form"}
+
 type FieldType struct {
 	Category FieldTypeCategory
 	Name     string
-	Items    *FieldType
+	// PkgPath is the package of the Go struct of FieldTypeCategoryObject.
+	PkgPath string
+	Items   *FieldType
 }
 
 type FieldTypeCategory int
@@ -58,14 +69,12 @@ type TypeItem struct {
 	Type string
 }
 
-func structInfo(input reflect.Type) []Struct {
-	list := make([]reflect.Type, 0, 1)
-	list = append(list, input)
-	result := make([]Struct, 0, 1)
-	structLink := make(map[string]int)
-
-	set := make(map[string]struct{})
+// structKey is the key of a struct by its package, as the short names of the structs can collide.
+func structKey(pkgPath string, name string) string {
+	return pkgPath + "." + name
+}
 
+func structInfo(input reflect.Type) []Struct {
 	realT := func(rt reflect.Type) reflect.Type {
 		for rt.Kind() == reflect.Ptr || rt.Kind() == reflect.Slice {
 			rt = rt.Elem() // use Elem to get the pointed-to-type
@@ -73,6 +82,13 @@ func structInfo(input reflect.Type) []Struct {
 		return rt
 	}
 
+	list := make([]reflect.Type, 0, 1)
+	list = append(list, realT(input))
+	result := make([]Struct, 0, 1)
+	structLink := make(map[string]int)
+
+	set := make(map[string]struct{})
+
 	var realFieldType func(rt reflect.Type) FieldType
 	realFieldType = func(rt reflect.Type) FieldType {
 		ft := FieldType{}
@@ -93,6 +109,7 @@ func structInfo(input reflect.Type) []Struct {
 			if rt.Kind() == reflect.Struct {
 				ft.Category = FieldTypeCategoryObject
 				ft.Name = rt.Name()
+				ft.PkgPath = rt.PkgPath()
 			} else {
 				ft.Category = FieldTypeCategoryBasic
 				ft.Name = rt.Name()
@@ -105,7 +122,7 @@ func structInfo(input reflect.Type) []Struct {
 	for len(list) > 0 {
 		process := append([]reflect.Type{}, list...)
 		for i := range list {
-			structLink[list[i].Name()]++
+			structLink[structKey(list[i].PkgPath(), list[i].Name())]++
 		}
 
 		list = make([]reflect.Type, 0)
@@ -115,14 +132,15 @@ func structInfo(input reflect.Type) []Struct {
 				continue
 			}
 
-			if _, exist := set[t.Name()]; !exist {
-				set[t.Name()] = struct{}{}
+			if _, exist := set[structKey(t.PkgPath(), t.Name())]; !exist {
+				set[structKey(t.PkgPath(), t.Name())] = struct{}{}
 			} else {
 				continue
 			}
 
 			curr := Struct{
-				Name: t.Name(),
+				Name:    t.Name(),
+				PkgPath: t.PkgPath(),
 			}
 
 			fieldNum := t.NumField()
@@ -145,6 +163,17 @@ func structInfo(input reflect.Type) []Struct {
 				}
 
 				name := fieldInfo.Name
+				var in string
+
+				for _, tag := range fieldInTags {
+					if tv := fieldInfo.Tag.Get(tag); tv != "" {
+						in = tag
+						if tn := strings.Split(tv, ",")[0]; tn != "" {
+							name = tn
+						}
+						break
+					}
+				}
 
 				if jt := fieldInfo.Tag.Get("json"); jt != "" {
 					name = strings.Split(jt, ",")[0]
@@ -155,11 +184,12 @@ func structInfo(input reflect.Type) []Struct {
 					Type:      realFieldType(fieldInfo.Type),
 					Remark:    fieldInfo.Tag.Get("remark"),
 					Anonymous: fieldInfo.Anonymous,
+					In:        in,
 				}
 
 				validate := fieldInfo.Tag.Get("validate")
 
-				if strings.Contains(validate, "required") {
+				if strings.Contains(validate, "required") || strings.Contains(fieldInfo.Tag.Get("vd"), "required") {
 					f.Required = true
 				}
 
@@ -179,15 +209,16 @@ func structInfo(input reflect.Type) []Struct {
 	// process anonymous.
 	tMap := make(map[string]Struct)
 	for i := range result {
-		tMap[result[i].Name] = result[i]
+		tMap[structKey(result[i].PkgPath, result[i].Name)] = result[i]
 	}
 	var extraField func(input Struct) []Field
 	extraField = func(input Struct) []Field {
 		rs := make([]Field, 0)
 		for i := range input.Field {
 			if input.Field[i].Anonymous {
-				si := tMap[input.Field[i].Type.Name]
-				structLink[input.Field[i].Type.Name]--
+				key := structKey(input.Field[i].Type.PkgPath, input.Field[i].Type.Name)
+				si := tMap[key]
+				structLink[key]--
 				asf := extraField(si)
 				rs = append(rs, asf...)
 			} else {
@@ -203,7 +234,7 @@ func structInfo(input reflect.Type) []Struct {
 
 	final := make([]Struct, 0, len(result))
 	for i := range result {
-		if structLink[result[i].Name] > 0 {
+		if structLink[structKey(result[i].PkgPath, result[i].Name)] > 0 {
 			final = append(final, result[i])
 		}
 	}
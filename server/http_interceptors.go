@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/metrics"
 	"github.com/ringbrew/gsv/service"
 	"github.com/ringbrew/gsv/tracex"
 	"go.opentelemetry.io/otel/baggage"
@@ -113,14 +114,14 @@ func (rec *HttpRecovery) ServeHTTP(rw http.ResponseWriter, r *http.Request, next
 				infos.Stack = stack
 			}
 
-			logger.Error(logger.NewEntry(r.Context()).WithMessage(fmt.Sprintf(panicText+".stack:[%s]", err, stack)))
+			serverLog.Error(logger.NewEntry(r.Context()).WithMessage(fmt.Sprintf(panicText+".stack:[%s]", err, stack)))
 			rec.Formatter.FormatPanicError(rw, r, infos)
 
 			if rec.PanicHandlerFunc != nil {
 				func() {
 					defer func() {
 						if err := recover(); err != nil {
-							logger.Error(logger.NewEntry(r.Context()).WithMessage(fmt.Sprintf("provided PanicHandlerFunc panic'd: %s, trace:\n%s", err, debug.Stack())))
+							serverLog.Error(logger.NewEntry(r.Context()).WithMessage(fmt.Sprintf("provided PanicHandlerFunc panic'd: %s, trace:\n%s", err, debug.Stack())))
 						}
 					}()
 					rec.PanicHandlerFunc(infos)
@@ -161,6 +162,58 @@ func (hlf *HttpLogFilter) Ignore(entry *logger.LogEntry) bool {
 	return false
 }
 
+// capturedBody passes the whole body to the handler, with the head read ahead for the log.
+type capturedBody struct {
+	io.Reader
+	io.Closer
+	head []byte
+	// more tells the body has the bytes after head, read counts the bytes read by the handler.
+	more bool
+	read int64
+}
+
+// captureBody reads the first limit bytes of rc ahead, limit <= 0 reads all of it.
+func captureBody(rc io.ReadCloser, limit int) (*capturedBody, error) {
+	var data []byte
+	var err error
+	if limit > 0 {
+		data, err = io.ReadAll(io.LimitReader(rc, int64(limit)+1))
+	} else {
+		data, err = io.ReadAll(rc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &capturedBody{
+		Reader: io.MultiReader(bytes.NewReader(data), rc),
+		Closer: rc,
+		head:   data,
+	}
+	if limit > 0 && len(data) > limit {
+		result.head = data[:limit]
+		result.more = true
+	}
+	return result, nil
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+// size returns the size of the body, by contentLength or the bytes read when the handler does not read it all.
+func (b *capturedBody) size(contentLength int64) int64 {
+	if !b.more {
+		return int64(len(b.head))
+	}
+	if contentLength > 0 {
+		return contentLength
+	}
+	return max(b.read, int64(len(b.head))+1)
+}
+
 const HttpLoggerKey = "HttpLogger"
 
 type HttpLogger struct {
@@ -191,8 +244,8 @@ func (hl *HttpLogger) SetIgnore(key string, value interface{}) {
 func (hl *HttpLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	startTime := time.Now()
 
-	// 1. 初始化一个变量来存储请求体内容
-	var bodyBytes []byte
+	// 1. 初始化一个变量来捕获请求体内容
+	var body *capturedBody
 
 	// 2. 检查 Content-Type，只对特定类型的请求读取 body
 	contentType := strings.ToLower(r.Header.Get("Content-Type"))
@@ -201,19 +254,15 @@ func (hl *HttpLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next ht
 		// 检查 r.Body 是否为 nil，避免 panic
 		if r.Body != nil {
 			var err error
-			// 核心步骤：读取 Body 内容
-			bodyBytes, err = io.ReadAll(r.Body)
+			// 核心步骤：只读取 MaxBodySize 以内的内容用于日志，完整的 Body 仍然交给下游
+			body, err = captureBody(r.Body, logger.Redaction().MaxBodySize)
 			if err != nil {
 				// 如果读取 Body 出错，记录一个错误日志并提前返回，避免继续处理一个坏的请求
-				logger.Error(logger.NewEntry(r.Context()).WithMessageF("failed to read request body"))
+				serverLog.Error(logger.NewEntry(r.Context()).WithMessageF("failed to read request body"))
 				http.Error(rw, "can't read body", http.StatusBadRequest)
 				return
 			}
-			// 关键步骤：将读取出来的内容重新放回 r.Body
-			// 使用 bytes.NewBuffer 创建一个实现了 io.Reader 的 buffer
-			// 使用 io.NopCloser 包装它，使其成为一个 io.ReadCloser
-			// 这样下游的 next(rw, r) 就可以像从未被读取过一样正常消费 Body
-			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			r.Body = body
 		}
 	}
 
@@ -238,15 +287,14 @@ func (hl *HttpLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next ht
 		WithExtra("status", status).
 		WithExtra("size", size)
 
-	if headerJson, err := json.Marshal(r.Header); err == nil {
+	redact := logger.Redaction()
+	if headerJson, err := json.Marshal(redact.Header(r.Header)); err == nil {
 		entry.WithExtra("header", string(headerJson))
 	}
 
-	// 5. 如果之前读取了 body，现在将其添加到日志中
-	// 确保 bodyBytes 不为 nil 且长度大于 0
-	if len(bodyBytes) > 0 {
-		// 直接将 []byte 转换为 string，效率很高
-		entry.WithExtra("body", string(bodyBytes))
+	// 5. 如果之前捕获了 body，现在将其添加到日志中，超出 MaxBodySize 的部分只记录字节数
+	if body != nil && len(body.head) > 0 {
+		entry.WithExtra("body", redact.PartialBody(contentType, body.head, body.size(r.ContentLength)))
 	}
 
 	if hl.Name != "" {
@@ -255,9 +303,9 @@ func (hl *HttpLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next ht
 
 	if !hl.f.Ignore(entry) {
 		if status >= http.StatusBadRequest {
-			logger.Error(entry.WithMessage(string(res.Dump())))
+			serverLog.Error(entry.WithMessage(redact.Body(rw.Header().Get("Content-Type"), res.Dump())))
 		} else {
-			logger.Info(entry.WithMessage("success"))
+			serverLog.Info(entry.WithMessage("success"))
 		}
 	}
 }
@@ -285,6 +333,8 @@ func (ht *HttpTracer) ServeHTTP(rw http.ResponseWriter, r *http.Request, next ht
 
 	bags, spanCtx := tracex.HttpExtract(ctx, propagation.HeaderCarrier(r.Header))
 	ctx = baggage.ContextWithBaggage(ctx, bags)
+	ctx = tracex.HttpDebug(ctx, propagation.HeaderCarrier(r.Header))
+	ctx = logger.WithFields(ctx, logger.FieldMethod, r.Method, logger.FieldPath, r.URL.Path, logger.FieldPeer, r.RemoteAddr)
 
 	tracer := tracex.NewConfig().TracerProvider.Tracer(
 		tracex.InstrumentationName,
@@ -305,6 +355,7 @@ func (ht *HttpTracer) ServeHTTP(rw http.ResponseWriter, r *http.Request, next ht
 
 	sc := span.SpanContext()
 	rpcCtx := tracex.NewServiceContext(sc.TraceID(), sc.SpanID())
+	tracex.CopyBaggage(ctx, rpcCtx)
 	ctx = service.NewContext(ctx, rpcCtx)
 
 	r = r.WithContext(ctx)
@@ -350,8 +401,9 @@ func WithHttpTracerName(name string) HttpTraceOption {
 }
 
 type HttpOption struct {
-	TraceOptions []HttpTraceOption
-	LogOptions   []HttpLogOption
+	TraceOptions   []HttpTraceOption
+	LogOptions     []HttpLogOption
+	MetricsOptions []metrics.HttpMetricsOption
 }
 
 func (ho HttpOption) Exec(handler Handler) {
@@ -375,6 +427,14 @@ func (ho HttpOption) Exec(handler Handler) {
 					ho.TraceOptions[i](ht)
 				}
 			}
+		case metrics.HttpMetricsKey:
+			if hm, ok := handler.(*metrics.HttpMetrics); !ok {
+				return
+			} else {
+				for i := range ho.MetricsOptions {
+					ho.MetricsOptions[i](hm)
+				}
+			}
 		}
 	}
 }
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ringbrew/gsv/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpLoggerBody(t *testing.T) {
+	prev := logger.Redaction()
+	defer logger.SetRedactPolicy(prev)
+	policy := logger.DefaultRedactPolicy()
+	policy.MaxBodySize = 8
+	logger.SetRedactPolicy(policy)
+
+	rec := logger.Record(t)
+
+	body := `{"name":"gsv","size":` + strings.Repeat("1", 64) + `}`
+	var received string
+	r := httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	NewHttpLogger().ServeHTTP(httptest.NewRecorder(), r, func(rw http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = string(data)
+	})
+
+	// the handler reads the whole body, and only MaxBodySize of it is captured for the log.
+	require.Equal(t, body, received)
+	entries := rec.Entries().Message("success")
+	require.Equal(t, 1, entries.Len())
+	require.Equal(t, `{"name":...(truncated 78 bytes)`, entries[0].Extra["body"])
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/metrics"
+	"github.com/ringbrew/gsv/server/binding/param"
 	"github.com/ringbrew/gsv/service"
+	"net"
 	"net/http"
 	"reflect"
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 type httpServer struct {
@@ -21,7 +25,15 @@ type httpServer struct {
 	srv         *Engine     //服务器
 	certFile    string      //证书路径
 	keyFile     string      //证书路径
+	tlsOpt      TLSOption
 	serviceList []service.Service
+	openAPI     OpenAPIOption
+	health      *healthHandler
+	drainOpt    DrainOption
+	metricsOpt  MetricsOption
+	logLevelOpt LogLevelOption
+	ready       chan struct{}
+	readyOnce   sync.Once
 }
 
 func newHttpServer(opts ...Option) *httpServer {
@@ -35,6 +47,7 @@ func newHttpServer(opts ...Option) *httpServer {
 	if opt.Name != "" {
 		opt.HttpOption.LogOptions = append(opt.HttpOption.LogOptions, WithHttpLoggerName(opt.Name))
 		opt.HttpOption.TraceOptions = append(opt.HttpOption.TraceOptions, WithHttpTracerName(opt.Name))
+		opt.HttpOption.MetricsOptions = append(opt.HttpOption.MetricsOptions, metrics.WithHttpMetricsName(opt.Name))
 	}
 
 	for i := range opt.HttpMiddleware {
@@ -43,25 +56,59 @@ func newHttpServer(opts ...Option) *httpServer {
 		s.Use(m)
 	}
 
-	return &httpServer{
-		host:   opt.Host,
-		port:   opt.Port,
-		router: mux.NewRouter(),
-		srv:    s,
+	result := &httpServer{
+		host:        opt.Host,
+		port:        opt.Port,
+		router:      mux.NewRouter(),
+		srv:         s,
+		certFile:    opt.CertFile,
+		keyFile:     opt.KeyFile,
+		tlsOpt:      opt.TLS,
+		openAPI:     opt.OpenAPI,
+		drainOpt:    opt.Drain,
+		metricsOpt:  opt.Metrics,
+		logLevelOpt: opt.LogLevel,
+		ready:       make(chan struct{}),
 	}
+
+	if opt.Health.Enable {
+		result.health = newHealthHandler(opt.Health)
+	}
+
+	return result
 }
 
 func (s *httpServer) Register(svc service.Service) error {
 	s.serviceList = append(s.serviceList, svc)
+	if checker, ok := svc.(HealthChecker); ok && s.health != nil {
+		s.health.registry().Register(svc.Name(), checker)
+	}
 	desc := svc.Description()
 	middlewares := s.srv.Handlers()
 
 	for ii := range desc.HttpRoute {
 		routeInfo := desc.HttpRoute[ii]
+		varNames := pathVarNames(routeInfo.Path)
+		handler := func(rw http.ResponseWriter, r *http.Request) {
+			service.RecordRoute(r.Context(), routeInfo)
+			ctx := service.NewRouteContext(r.Context(), routeInfo)
+			ctx = logger.WithFields(ctx, logger.FieldRoute, routeInfo.Path)
+			if len(varNames) > 0 {
+				vars := mux.Vars(r)
+				ps := make(param.Params, 0, len(varNames))
+				for _, name := range varNames {
+					if v, ok := vars[name]; ok {
+						ps = append(ps, param.Param{Key: name, Value: v})
+					}
+				}
+				ctx = param.NewContext(ctx, ps)
+			}
+			routeInfo.Handler(rw, r.WithContext(ctx))
+		}
 		if routeInfo.Method == service.MethodAll {
-			s.router.HandleFunc(routeInfo.Path, routeInfo.Handler)
+			s.router.HandleFunc(routeInfo.Path, handler)
 		} else {
-			s.router.HandleFunc(routeInfo.Path, routeInfo.Handler).Methods(routeInfo.Method)
+			s.router.HandleFunc(routeInfo.Path, handler).Methods(routeInfo.Method)
 		}
 	}
 
@@ -76,35 +123,141 @@ func (s *httpServer) Register(svc service.Service) error {
 	return nil
 }
 
-func (s *httpServer) Run(ctx context.Context) {
+// pathVarNames returns the variable names of a mux path template in order, like [id name] for '/users/{id}/{name:[a-z]+}'.
+func pathVarNames(path string) []string {
+	var result []string
+	level, start := 0, 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			if level == 0 {
+				start = i + 1
+			}
+			level++
+		case '}':
+			level--
+			if level == 0 {
+				name, _, _ := strings.Cut(path[start:i], ":")
+				result = append(result, strings.TrimSpace(name))
+			}
+		}
+	}
+	return result
+}
+
+// Run binds the listener and serves until ctx is done or serving fails, the errors of binding and serving
+// are returned. Ready is closed once the listener is bound.
+func (s *httpServer) Run(ctx context.Context) error {
+	var reloader *tlsReloader
+	if s.certFile != "" && s.keyFile != "" {
+		r, err := newTLSReloader(s.certFile, s.keyFile, s.tlsOpt)
+		if err != nil {
+			return err
+		}
+		reloader = r
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return fmt.Errorf("http server listen error: %w", err)
+	}
+
+	if s.openAPI.Enable {
+		s.registerOpenAPI()
+	}
+	if s.health != nil {
+		s.registerHealth()
+	}
+	if s.metricsOpt.Enable {
+		s.router.Handle(s.metricsOpt.path(), s.metricsOpt.handler()).Methods(http.MethodGet)
+	}
+	if s.logLevelOpt.Enable {
+		s.router.Handle(s.logLevelOpt.path(), logger.LevelHandler())
+	}
 	s.srv.UseHandler(s.router)
 
 	hs := &http.Server{
 		Addr:    fmt.Sprintf(":%d", s.port),
 		Handler: s.srv,
 	}
+
+	errCh := make(chan error, 1)
 	go func() {
-		<-ctx.Done()
-		logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server stop listen on: [%d]", s.port)))
+		if reloader != nil {
+			serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server start listen tls on: [%d]", s.port)))
 
-		if err := hs.Shutdown(context.Background()); err != nil {
-			logger.Fatal(logger.NewEntry().WithMessage(fmt.Sprintf("failed to shutdown http server: %s", err.Error())))
+			hs.TLSConfig = reloader.ServerConfig()
+			if err := hs.ServeTLS(lis, "", ""); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("http server listen tls error: %w", err)
+			}
+		} else {
+			serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server start listen on: [%d]", s.port)))
+
+			if err := hs.Serve(lis); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("http server listen error: %w", err)
+			}
 		}
+		close(errCh)
 	}()
 
-	if s.certFile != "" && s.keyFile != "" {
-		logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server start listen tls on: [%d]", s.port)))
+	if s.health != nil {
+		s.health.registry().SetServing(true)
+	}
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
 
-		if err := hs.ListenAndServeTLS(s.certFile, s.keyFile); err != nil && err != http.ErrServerClosed {
-			logger.Fatal(logger.NewEntry().WithMessage(fmt.Sprintf("http server listen tls error: %s", err.Error())))
-		}
-	} else {
-		logger.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server start listen on: [%d]", s.port)))
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+
+	s.drain(hs)
+	<-errCh
 
-		if err := hs.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal(logger.NewEntry().WithMessage(fmt.Sprintf("http server listen error: %s", err.Error())))
+	return err
+}
+
+// Ready is closed once the listener is bound.
+func (s *httpServer) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *httpServer) registerOpenAPI() {
+	h := newOpenAPIHandler(s.openAPI, s.Doc)
+	s.router.HandleFunc(h.opt.Path, h.ServeSpec).Methods(http.MethodGet)
+	if h.opt.UIPath != "-" {
+		s.router.HandleFunc(h.opt.UIPath, h.ServeUI).Methods(http.MethodGet)
+		if h.embeddedAssets() {
+			s.router.PathPrefix(h.assetsPath() + "/").Handler(h.ServeAssets()).Methods(http.MethodGet)
 		}
 	}
+	serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server serve openapi on: [%s]", h.opt.Path)))
+}
+
+// drain stops hs by the sequence of DrainOption, the http server has no node to deregister.
+func (s *httpServer) drain(hs *http.Server) {
+	logDrain("http server", "mark not ready")
+	if s.health != nil {
+		s.health.registry().SetServing(false)
+	}
+
+	s.drainOpt.waitPropagation("http server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainOpt.timeout())
+	defer cancel()
+
+	if err := drainHttp(ctx, "http server", hs); err != nil {
+		serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("failed to shutdown http server: %s", err.Error())))
+	}
+	serverLog.Info(logger.NewEntry().WithMessage(fmt.Sprintf("http server stop listen on: [%d]", s.port)))
+
+	s.drainOpt.flushTrace("http server")
+}
+
+func (s *httpServer) registerHealth() {
+	s.router.HandleFunc(s.health.opt.LivePath, s.health.ServeLive).Methods(http.MethodGet, http.MethodHead)
+	s.router.HandleFunc(s.health.opt.ReadyPath, s.health.ServeReady).Methods(http.MethodGet, http.MethodHead)
 }
 
 func (s *httpServer) Doc() []DocService {
@@ -122,7 +275,11 @@ func (s *httpServer) Doc() []DocService {
 		for ii := range desc.HttpRoute {
 			dhr := desc.HttpRoute[ii]
 
-			funcPtr := reflect.ValueOf(dhr.Handler).Pointer()
+			var target interface{} = dhr.Handler
+			if dhr.Target != nil {
+				target = dhr.Target
+			}
+			funcPtr := reflect.ValueOf(target).Pointer()
 			funcName := runtime.FuncForPC(funcPtr).Name()
 
 			r := regexp.MustCompile(`\(\*(\w*Handler)\).(\w+)-fm`)
@@ -131,7 +288,7 @@ func (s *httpServer) Doc() []DocService {
 			var module string
 			var action string
 			if len(res) != 3 {
-				logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("Doc compile error: %v", res)))
+				serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("Doc compile error: %v", res)))
 			} else {
 				if res[1] != "Handler" {
 					module = strings.ReplaceAll(res[1], "Handler", "")
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ringbrew/gsv/service"
+	"github.com/stretchr/testify/require"
+)
+
+type pathService struct{}
+
+type pathReq struct {
+	ID   int64  `path:"id"`
+	Name string `path:"name"`
+}
+
+func (p *pathService) Name() string {
+	return "path"
+}
+
+func (p *pathService) Remark() string {
+	return "path service"
+}
+
+func (p *pathService) Description() service.Description {
+	var routes service.HttpRouteCollector
+	routes.Add(service.NewTypedRoute(service.MethodGet, "/users/{id}/{name:[a-z]+}", func(ctx context.Context, req *pathReq) (*pathReq, error) {
+		return req, nil
+	}))
+	return service.Description{Valid: true, HttpRoute: routes}
+}
+
+func TestPathParams(t *testing.T) {
+	require.Equal(t, []string{"id", "name"}, pathVarNames("/users/{id}/{name:[a-z]{1,3}}"))
+
+	s := newHttpServer(Option{})
+	require.NoError(t, s.Register(&pathService{}))
+
+	rw := httptest.NewRecorder()
+	s.router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/users/42/gsv", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.JSONEq(t, `{"ID":42,"Name":"gsv"}`, rw.Body.String())
+}
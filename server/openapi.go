@@ -0,0 +1,534 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"html"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const openAPIVersion = "3.1.0"
+
+// OpenAPIInfo describes the api document exported by OpenAPI.
+type OpenAPIInfo struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []string
+}
+
+// OpenAPIOption enables the built-in routes serving the OpenAPI document and Swagger UI on the http server.
+type OpenAPIOption struct {
+	Enable bool
+	Info   OpenAPIInfo
+	// Path serves the json document, default "/openapi.json".
+	Path string
+	// UIPath serves the Swagger UI, default "/swagger". Set "-" to disable it.
+	UIPath string
+	// UIAssetsURL is where the swagger-ui-dist assets are loaded from, the ones embedded are served under
+	// UIPath + "/assets" when it is empty.
+	UIAssetsURL string
+}
+
+const (
+	defaultOpenAPIPath   = "/openapi.json"
+	defaultSwaggerUIPath = "/swagger"
+)
+
+type openAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIDocInfo                          `json:"info"`
+	Servers    []openAPIServer                         `json:"servers,omitempty"`
+	Tags       []openAPITag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIDocInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	Summary     string                     `json:"summary,omitempty"`
+	OperationId string                     `json:"operationId,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema  *openAPISchema  `json:"schema,omitempty"`
+	Example json.RawMessage `json:"example,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Items       *openAPISchema            `json:"items,omitempty"`
+	Properties  map[string]*openAPISchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+}
+
+// OpenAPI exports the docs as an OpenAPI 3.1 json document.
+func OpenAPI(docs []DocService, info OpenAPIInfo) ([]byte, error) {
+	doc := openAPIDoc{
+		OpenAPI: openAPIVersion,
+		Info: openAPIDocInfo{
+			Title:       info.Title,
+			Version:     info.Version,
+			Description: info.Description,
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: make(map[string]*openAPISchema),
+		},
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = "gsv"
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = "1.0.0"
+	}
+	for _, v := range info.Servers {
+		doc.Servers = append(doc.Servers, openAPIServer{URL: v})
+	}
+
+	operationIds := make(map[string]int)
+	names := openAPISchemaNames(docs)
+
+	for _, ds := range docs {
+		doc.Tags = append(doc.Tags, openAPITag{Name: ds.Key, Description: ds.Name})
+
+		for _, api := range ds.Api {
+			if api.Path == "" || api.Method == DocMethodGRPC {
+				continue
+			}
+			path, pathParams := openAPIPath(api.Path)
+			reqRoot := addOpenAPISchemas(doc.Components.Schemas, names, api.Request)
+			respRoot := addOpenAPISchemas(doc.Components.Schemas, names, api.Response)
+
+			for _, method := range openAPIMethods(api.Method) {
+				op := &openAPIOperation{
+					Summary:     api.Name,
+					OperationId: openAPIOperationId(operationIds, ds.Key, api, method),
+					Tags:        []string{ds.Key},
+					Responses:   make(map[string]openAPIResponse),
+				}
+
+				for _, p := range pathParams {
+					param := openAPIParameter{
+						Name:     p,
+						In:       "path",
+						Required: true,
+						Schema:   &openAPISchema{Type: "string"},
+					}
+					// the parameter takes the type of the field bound to it.
+					if reqRoot != nil {
+						for _, f := range reqRoot.Field {
+							if f.In == "path" && f.Name == p && f.Type.Category == FieldTypeCategoryBasic {
+								param.Description = f.Remark
+								param.Schema = openAPIFieldSchema(names, f.Type)
+								break
+							}
+						}
+					}
+					op.Parameters = append(op.Parameters, param)
+				}
+
+				contentType := api.ContentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+
+				if reqRoot != nil {
+					inBody := method != "get" && method != "delete" && method != "head"
+					for _, f := range reqRoot.Field {
+						in := f.In
+						if in == "" && !inBody {
+							in = "query"
+						}
+						if in == "" || in == "form" || in == "path" || f.Type.Category == FieldTypeCategoryObject {
+							continue
+						}
+						op.Parameters = append(op.Parameters, openAPIParameter{
+							Name:        f.Name,
+							In:          in,
+							Description: f.Remark,
+							Required:    f.Required,
+							Schema:      openAPIFieldSchema(names, f.Type),
+						})
+					}
+					if inBody {
+						op.RequestBody = &openAPIRequestBody{
+							Required: true,
+							Content: map[string]openAPIMediaType{
+								contentType: {
+									Schema:  &openAPISchema{Ref: openAPIRef(names.name(reqRoot.PkgPath, reqRoot.Name))},
+									Example: openAPIExample(api.RequestExample),
+								},
+							},
+						}
+					}
+				}
+
+				resp := openAPIResponse{Description: "OK"}
+				if respRoot != nil {
+					resp.Content = map[string]openAPIMediaType{
+						contentType: {
+							Schema:  &openAPISchema{Ref: openAPIRef(names.name(respRoot.PkgPath, respRoot.Name))},
+							Example: openAPIExample(api.ResponseExample),
+						},
+					}
+				}
+				op.Responses["200"] = resp
+
+				if _, exist := doc.Paths[path]; !exist {
+					doc.Paths[path] = make(map[string]*openAPIOperation)
+				}
+				doc.Paths[path][method] = op
+			}
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// openAPIPath converts a mux path template into an OpenAPI one, like '/users/{id:[0-9]+}' to '/users/{id}'.
+func openAPIPath(path string) (string, []string) {
+	names := pathVarNames(path)
+	if len(names) == 0 {
+		return path, nil
+	}
+
+	var sb strings.Builder
+	level := 0
+	idx := 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			if level == 0 {
+				sb.WriteString("{" + names[idx] + "}")
+				idx++
+			}
+			level++
+			continue
+		case '}':
+			level--
+			continue
+		}
+		if level == 0 {
+			sb.WriteByte(path[i])
+		}
+	}
+	return sb.String(), names
+}
+
+func openAPIMethods(method string) []string {
+	if method == "" || method == "all" {
+		return []string{"get", "post"}
+	}
+	return []string{strings.ToLower(method)}
+}
+
+func openAPIOperationId(exist map[string]int, serviceKey string, api DocApi, method string) string {
+	id := api.Action
+	if id == "" {
+		id = strings.Trim(strings.NewReplacer("/", "_", "{", "", "}", "").Replace(api.Path), "_")
+	}
+	if api.Module != "" {
+		id = api.Module + "_" + id
+	}
+	if serviceKey != "" {
+		id = serviceKey + "_" + id
+	}
+	if openAPIMethods(api.Method)[0] != method {
+		id = id + "_" + method
+	}
+	exist[id]++
+	if n := exist[id]; n > 1 {
+		id = fmt.Sprintf("%s_%d", id, n)
+	}
+	return id
+}
+
+func openAPIRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+func openAPIExample(example string) json.RawMessage {
+	if example == "" || !json.Valid([]byte(example)) {
+		return nil
+	}
+	return json.RawMessage(example)
+}
+
+// openAPINames are the names of the schemas by structKey, the short names of the structs are qualified by their
+// packages when they collide, like 'a.User' and 'b.User'.
+type openAPINames map[string]string
+
+func openAPISchemaNames(docs []DocService) openAPINames {
+	pkgs := make(map[string]map[string]struct{})
+	add := func(pkgPath string, name string) {
+		if name == "" {
+			return
+		}
+		if _, ok := pkgs[name]; !ok {
+			pkgs[name] = make(map[string]struct{})
+		}
+		pkgs[name][pkgPath] = struct{}{}
+	}
+	var addType func(ft FieldType)
+	addType = func(ft FieldType) {
+		if ft.Category == FieldTypeCategoryObject {
+			add(ft.PkgPath, ft.Name)
+		}
+		if ft.Items != nil {
+			addType(*ft.Items)
+		}
+	}
+	for _, ds := range docs {
+		for _, api := range ds.Api {
+			for _, list := range [][]Struct{api.Request, api.Response} {
+				for _, st := range list {
+					add(st.PkgPath, st.Name)
+					for _, f := range st.Field {
+						addType(f.Type)
+					}
+				}
+			}
+		}
+	}
+
+	result := make(openAPINames)
+	for name, set := range pkgs {
+		if len(set) == 1 {
+			for pkgPath := range set {
+				result[structKey(pkgPath, name)] = name
+			}
+			continue
+		}
+
+		// the last elements of the packages are taken, or the whole paths if they still collide.
+		short := make(map[string]int)
+		for pkgPath := range set {
+			short[path.Base(pkgPath)]++
+		}
+		for pkgPath := range set {
+			qualified := path.Base(pkgPath)
+			if short[qualified] > 1 {
+				qualified = pkgPath
+			}
+			result[structKey(pkgPath, name)] = openAPISchemaNameReplacer.Replace(qualified) + "." + name
+		}
+	}
+	return result
+}
+
+// openAPISchemaNameReplacer keeps the schema names in the charset of OpenAPI, which is [a-zA-Z0-9.\-_].
+var openAPISchemaNameReplacer = strings.NewReplacer("/", "_", "~", "_", "+", "_")
+
+func (n openAPINames) name(pkgPath string, name string) string {
+	if v, ok := n[structKey(pkgPath, name)]; ok {
+		return v
+	}
+	return name
+}
+
+// addOpenAPISchemas adds the structs into schemas and returns the root struct.
+func addOpenAPISchemas(schemas map[string]*openAPISchema, names openAPINames, list []Struct) *Struct {
+	var root *Struct
+	for i := range list {
+		s := list[i]
+		if s.Root && root == nil {
+			root = &list[i]
+		}
+		if s.Name == "" {
+			continue
+		}
+		name := names.name(s.PkgPath, s.Name)
+		if _, exist := schemas[name]; exist {
+			continue
+		}
+		schema := &openAPISchema{
+			Type:       "object",
+			Properties: make(map[string]*openAPISchema),
+		}
+		for _, f := range s.Field {
+			schema.Properties[f.Name] = openAPIFieldSchema(names, f.Type)
+			if f.Remark != "" {
+				schema.Properties[f.Name].Description = f.Remark
+			}
+			if f.Required {
+				schema.Required = append(schema.Required, f.Name)
+			}
+		}
+		sort.Strings(schema.Required)
+		schemas[name] = schema
+	}
+	return root
+}
+
+func openAPIFieldSchema(names openAPINames, ft FieldType) *openAPISchema {
+	switch ft.Category {
+	case FieldTypeCategoryArray:
+		result := &openAPISchema{Type: "array"}
+		if ft.Items != nil {
+			result.Items = openAPIFieldSchema(names, *ft.Items)
+		}
+		return result
+	case FieldTypeCategoryObject:
+		if ft.PkgPath == "time" && ft.Name == "Time" {
+			return &openAPISchema{Type: "string", Format: "date-time"}
+		}
+		if ft.Name == "" {
+			return &openAPISchema{Type: "object"}
+		}
+		return &openAPISchema{Ref: openAPIRef(names.name(ft.PkgPath, ft.Name))}
+	}
+
+	switch ft.Name {
+	case "string":
+		return &openAPISchema{Type: "string"}
+	case "bytes":
+		return &openAPISchema{Type: "string", Format: "byte"}
+	case "bool":
+		return &openAPISchema{Type: "boolean"}
+	case "int", "int64", "uint", "uint64":
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32":
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case "float32":
+		return &openAPISchema{Type: "number", Format: "float"}
+	case "float64":
+		return &openAPISchema{Type: "number", Format: "double"}
+	case "Duration":
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	}
+	// map, interface and the other unnamed types.
+	return &openAPISchema{}
+}
+
+const swaggerUITemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>%s</title>
+  <link rel="stylesheet" href="%s/swagger-ui.css" />
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="%s/swagger-ui-bundle.js" crossorigin></script>
+<script>
+  window.onload = () => {
+    window.ui = SwaggerUIBundle({url: '%s', dom_id: '#swagger-ui'});
+  };
+</script>
+</body>
+</html>
+`
+
+// openAPIHandler serves the document of a server and the Swagger UI page.
+type openAPIHandler struct {
+	opt  OpenAPIOption
+	doc  func() []DocService
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func newOpenAPIHandler(opt OpenAPIOption, doc func() []DocService) *openAPIHandler {
+	if opt.Path == "" {
+		opt.Path = defaultOpenAPIPath
+	}
+	if opt.UIPath == "" {
+		opt.UIPath = defaultSwaggerUIPath
+	}
+	opt.UIAssetsURL = strings.TrimSuffix(opt.UIAssetsURL, "/")
+
+	return &openAPIHandler{
+		opt: opt,
+		doc: doc,
+	}
+}
+
+func (h *openAPIHandler) ServeSpec(rw http.ResponseWriter, r *http.Request) {
+	// the services are all registered before the server runs, so the document is built once.
+	h.once.Do(func() {
+		h.data, h.err = OpenAPI(h.doc(), h.opt.Info)
+	})
+	if h.err != nil {
+		http.Error(rw, h.err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(h.data)
+}
+
+// embeddedAssets reports whether the Swagger UI assets are served from the ones embedded.
+func (h *openAPIHandler) embeddedAssets() bool {
+	return h.opt.UIAssetsURL == ""
+}
+
+func (h *openAPIHandler) assetsPath() string {
+	return strings.TrimSuffix(h.opt.UIPath, "/") + "/assets"
+}
+
+// ServeAssets serves the swagger-ui-dist assets embedded under assetsPath.
+func (h *openAPIHandler) ServeAssets() http.Handler {
+	return http.StripPrefix(h.assetsPath(), http.FileServer(http.FS(swaggerFiles.FS)))
+}
+
+func (h *openAPIHandler) ServeUI(rw http.ResponseWriter, r *http.Request) {
+	title := h.opt.Info.Title
+	if title == "" {
+		title = "gsv"
+	}
+	assets := h.opt.UIAssetsURL
+	if h.embeddedAssets() {
+		assets = h.assetsPath()
+	}
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(rw, swaggerUITemplate, html.EscapeString(title), assets, assets, h.opt.Path)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI(t *testing.T) {
+	s := newHttpServer(Option{OpenAPI: OpenAPIOption{Enable: true, Info: OpenAPIInfo{Title: "path"}}})
+	require.NoError(t, s.Register(&pathService{}))
+	s.registerOpenAPI()
+
+	rw := httptest.NewRecorder()
+	s.router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, defaultOpenAPIPath, nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+	require.Equal(t, openAPIVersion, doc["openapi"])
+
+	paths := doc["paths"].(map[string]interface{})
+	op := paths["/users/{id}/{name}"].(map[string]interface{})["get"].(map[string]interface{})
+	require.Len(t, op["parameters"], 2)
+	// the path parameters take the types of the fields bound.
+	params := op["parameters"].([]interface{})
+	require.Equal(t, "integer", params[0].(map[string]interface{})["schema"].(map[string]interface{})["type"])
+	require.Equal(t, "string", params[1].(map[string]interface{})["schema"].(map[string]interface{})["type"])
+
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	require.Contains(t, schemas, "pathReq")
+
+	rw = httptest.NewRecorder()
+	s.router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, defaultSwaggerUIPath, nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Contains(t, rw.Body.String(), "SwaggerUIBundle")
+	require.Contains(t, rw.Body.String(), defaultSwaggerUIPath+"/assets/swagger-ui-bundle.js")
+
+	// the assets are embedded.
+	rw = httptest.NewRecorder()
+	s.router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, defaultSwaggerUIPath+"/assets/swagger-ui-bundle.js", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.NotZero(t, rw.Body.Len())
+}
+
+func TestOpenAPISchemaNames(t *testing.T) {
+	user := func(pkgPath string) []Struct {
+		return []Struct{{Name: "User", PkgPath: pkgPath, Root: true, Field: []Field{
+			{Name: "friend", Type: FieldType{Category: FieldTypeCategoryObject, Name: "User", PkgPath: pkgPath}},
+		}}}
+	}
+	data, err := OpenAPI([]DocService{{Key: "user", Api: []DocApi{
+		{Path: "/a", Method: "post", Request: user("example.com/a")},
+		{Path: "/b", Method: "post", Request: user("example.com/b")},
+		{Path: "/c", Method: "post", Request: user("example.org/a")},
+	}}}, OpenAPIInfo{})
+	require.NoError(t, err)
+
+	var doc openAPIDoc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Len(t, doc.Components.Schemas, 3)
+	require.Contains(t, doc.Components.Schemas, "b.User")
+	require.Equal(t, openAPIRef("example.com_a.User"), doc.Components.Schemas["example.com_a.User"].Properties["friend"].Ref)
+	require.Equal(t, openAPIRef("example.org_a.User"), doc.Paths["/c"]["post"].RequestBody.Content["application/json"].Schema.Ref)
+}
+
+func TestOpenAPITime(t *testing.T) {
+	data, err := OpenAPI([]DocService{{Key: "event", Api: []DocApi{
+		{Path: "/events", Method: "post", Request: []Struct{
+			{Name: "Event", PkgPath: "example.com/event", Root: true, Field: []Field{
+				{Name: "at", Type: FieldType{Category: FieldTypeCategoryObject, Name: "Time", PkgPath: "time"}},
+				{Name: "slot", Type: FieldType{Category: FieldTypeCategoryObject, Name: "Time", PkgPath: "example.com/event"}},
+			}},
+			{Name: "Time", PkgPath: "example.com/event"},
+		}},
+	}}}, OpenAPIInfo{})
+	require.NoError(t, err)
+
+	var doc openAPIDoc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	event := doc.Components.Schemas["Event"]
+	require.Equal(t, "date-time", event.Properties["at"].Format)
+	// the other types named Time are the structs of their own.
+	require.Equal(t, openAPIRef("event.Time"), event.Properties["slot"].Ref)
+}
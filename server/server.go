@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"github.com/ringbrew/gsv/discovery"
 	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/metrics"
 	"github.com/ringbrew/gsv/service"
+	"github.com/ringbrew/gsv/tracex"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/stats"
+	"net/http"
 	"runtime/debug"
 )
 
@@ -22,6 +25,7 @@ const TagExternal = "external"
 
 type Option struct {
 	Name           string
+	Version        string
 	Host           string
 	External       []string
 	Port           int
@@ -29,6 +33,7 @@ type Option struct {
 	ServerRegister discovery.NodeRegister
 	CertFile       string
 	KeyFile        string
+	TLS            TLSOption
 	NodeId         string
 
 	//grpc option.
@@ -40,6 +45,80 @@ type Option struct {
 	//http option
 	HttpMiddleware []Handler
 	HttpOption     HttpOption
+
+	//doc option
+	OpenAPI OpenAPIOption
+
+	//health option
+	Health HealthOption
+
+	//drain option
+	Drain DrainOption
+
+	//metrics option
+	Metrics MetricsOption
+
+	//log level option
+	LogLevel LogLevelOption
+}
+
+// serverLog is the named logger of the package, its level can be changed apart by logger.SetNamedLevel("server", ...).
+var serverLog = logger.Named("server")
+
+// LogLevelOption serves logger.LevelHandler on the http server and the grpc gateway, to change the log levels
+// at runtime. It should be enabled only when the port is not exposed publicly.
+type LogLevelOption struct {
+	Enable bool
+	// Path is '/admin/log/level' by default.
+	Path string
+}
+
+func (o LogLevelOption) path() string {
+	if o.Path == "" {
+		return logger.DefaultLevelPath
+	}
+	return o.Path
+}
+
+// MetricsOption serves the prometheus metrics on the http server and the grpc gateway. It is disabled by default,
+// and should be enabled only when the port is not exposed publicly.
+type MetricsOption struct {
+	Enable bool
+	// Path is '/metrics' by default.
+	Path string
+	// Handler is metrics.Handler() by default.
+	Handler http.Handler
+}
+
+func (o MetricsOption) path() string {
+	if o.Path == "" {
+		return metrics.DefaultPath
+	}
+	return o.Path
+}
+
+func (o MetricsOption) handler() http.Handler {
+	if o.Handler == nil {
+		return metrics.Handler()
+	}
+	return o.Handler
+}
+
+// TraceOption fills the resource attributes of opt by the server, like tracex.Init(serverOpt.TraceOption(traceOpt)).
+func (o Option) TraceOption(opt tracex.Option) tracex.Option {
+	if opt.ServiceName == "" {
+		opt.ServiceName = o.Name
+	}
+	if opt.ServiceVersion == "" {
+		opt.ServiceVersion = o.Version
+	}
+	if opt.NodeId == "" {
+		opt.NodeId = o.NodeId
+	}
+	if opt.Host == "" {
+		opt.Host = o.Host
+	}
+	return opt
 }
 
 func Classic() Option {
@@ -48,21 +127,24 @@ func Classic() Option {
 		ProxyPort: 3001,
 		StreamInterceptors: []grpc.StreamServerInterceptor{
 			RecoverStreamInterceptor(func(panic interface{}) {
-				logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server panic:[%v] with stack[%s]", panic, string(debug.Stack()))))
+				serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server panic:[%v] with stack[%s]", panic, string(debug.Stack()))))
 			}),
 			TraceStreamServerInterceptor(),
+			metrics.StreamServerInterceptor(),
 		},
 		UnaryInterceptors: []grpc.UnaryServerInterceptor{
 			RecoverUnaryInterceptor(func(panic interface{}) {
-				logger.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server panic:[%v] with stack[%s]", panic, string(debug.Stack()))))
+				serverLog.Error(logger.NewEntry().WithMessage(fmt.Sprintf("server panic:[%v] with stack[%s]", panic, string(debug.Stack()))))
 			}),
 			TraceUnaryInterceptor(),
 			LogUnaryInterceptor(),
+			metrics.UnaryServerInterceptor(),
 		},
 		HttpMiddleware: []Handler{
 			NewHttpRecovery(),
 			NewHttpTracer(),
-			NewHttpLogger()},
+			NewHttpLogger(),
+			metrics.NewHttpMetrics()},
 	}
 }
 
@@ -76,7 +158,10 @@ type GetKeyer interface {
 
 type Server interface {
 	Register(service service.Service) error
-	Run(ctx context.Context)
+	// Run serves until ctx is done, it returns the error of binding, registering or serving.
+	Run(ctx context.Context) error
+	// Ready is closed once the listeners are bound and the node is serving.
+	Ready() <-chan struct{}
 	Doc() []DocService
 }
 
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReady(t *testing.T) {
+	for _, typ := range []Type{GRPC, HTTP} {
+		s := NewServer(typ, &Option{EnableGrpcGateway: true, Health: HealthOption{Enable: true}})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error, 1)
+		go func() {
+			result <- s.Run(ctx)
+		}()
+
+		select {
+		case <-s.Ready():
+		case err := <-result:
+			t.Fatalf("%s server run error: %v", typ, err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s server is not ready", typ)
+		}
+
+		cancel()
+		require.NoError(t, <-result)
+	}
+}
+
+func TestRunListenError(t *testing.T) {
+	lis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer lis.Close()
+	port := lis.Addr().(*net.TCPAddr).Port
+
+	require.Error(t, NewServer(HTTP, &Option{Port: port}).Run(context.Background()))
+	require.Error(t, NewServer(GRPC, &Option{Port: port}).Run(context.Background()))
+}
+
+func TestClassicAdminDisabled(t *testing.T) {
+	// the admin endpoints are opt-in, as the ports of Classic may be exposed publicly.
+	opt := Classic()
+	require.False(t, opt.Metrics.Enable)
+	require.False(t, opt.LogLevel.Enable)
+}
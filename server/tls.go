@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/ringbrew/gsv/internal/tlsx"
+	"time"
+)
+
+// TLSOption configures the TLS of the servers, TLS is enabled when Option.CertFile and Option.KeyFile are set.
+type TLSOption struct {
+	// ClientCAFile is the pem bundle to verify client certificates by, mTLS is enabled when it is set.
+	ClientCAFile string
+	// ClientAuth is tls.RequireAndVerifyClientCert by default when ClientCAFile is set.
+	ClientAuth tls.ClientAuthType
+	// MinVersion is tls.VersionTLS12 by default.
+	MinVersion uint16
+	// CipherSuites limits the cipher suites of TLS 1.2 and below, the defaults of crypto/tls are used when empty.
+	CipherSuites []uint16
+	// ReloadInterval is how often the files are checked for changes, 1m by default, negative disables reloading.
+	ReloadInterval time.Duration
+	// LoopbackCertFile and LoopbackKeyFile are the client certificate the gateway presents to its own grpc server
+	// under mTLS, it should be issued by a CA in ClientCAFile for the client authentication. The server certificate
+	// is presented when they are not set, which should allow the client authentication then.
+	LoopbackCertFile string
+	LoopbackKeyFile  string
+}
+
+// tlsFiles are the certificates and the client CAs loaded from disk.
+type tlsFiles struct {
+	cert     *tls.Certificate
+	loopback *tls.Certificate
+	config   *tls.Config
+}
+
+// tlsReloader holds the tlsFiles, they are reloaded on handshakes once the files are modified.
+type tlsReloader struct {
+	*tlsx.Reloader[tlsFiles]
+	opt TLSOption
+}
+
+func newTLSReloader(certFile string, keyFile string, opt TLSOption) (*tlsReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("tls cert file and key file are required")
+	}
+	if opt.MinVersion == 0 {
+		opt.MinVersion = tls.VersionTLS12
+	}
+	if (opt.LoopbackCertFile == "") != (opt.LoopbackKeyFile == "") {
+		return nil, errors.New("tls loopback cert file and key file should be set together")
+	}
+	if opt.ClientCAFile != "" && opt.ClientAuth == tls.NoClientCert {
+		opt.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	files := []string{certFile, keyFile}
+	if opt.ClientCAFile != "" {
+		files = append(files, opt.ClientCAFile)
+	}
+	if opt.LoopbackCertFile != "" {
+		files = append(files, opt.LoopbackCertFile, opt.LoopbackKeyFile)
+	}
+
+	r, err := tlsx.NewReloader(files, opt.ReloadInterval, serverLog, func() (tlsFiles, error) {
+		return loadTLSFiles(certFile, keyFile, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tlsReloader{Reloader: r, opt: opt}, nil
+}
+
+func loadTLSFiles(certFile string, keyFile string, opt TLSOption) (tlsFiles, error) {
+	cert, err := tlsx.LoadKeyPair(certFile, keyFile)
+	if err != nil {
+		return tlsFiles{}, err
+	}
+
+	loopback := cert
+	if opt.LoopbackCertFile != "" {
+		if loopback, err = tlsx.LoadKeyPair(opt.LoopbackCertFile, opt.LoopbackKeyFile); err != nil {
+			return tlsFiles{}, err
+		}
+	}
+
+	var clientCAs *x509.CertPool
+	if opt.ClientCAFile != "" {
+		if clientCAs, err = tlsx.LoadCertPool(opt.ClientCAFile); err != nil {
+			return tlsFiles{}, err
+		}
+	}
+
+	return tlsFiles{
+		cert:     cert,
+		loopback: loopback,
+		config: &tls.Config{
+			Certificates: []tls.Certificate{*cert},
+			ClientCAs:    clientCAs,
+			ClientAuth:   opt.ClientAuth,
+			MinVersion:   opt.MinVersion,
+			CipherSuites: opt.CipherSuites,
+			NextProtos:   []string{"h2", "http/1.1"},
+		},
+	}, nil
+}
+
+// ServerConfig returns the config for listeners, every handshake takes the latest certificate and client CAs.
+func (r *tlsReloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.opt.MinVersion,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Current().cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.Current().config, nil
+		},
+	}
+}
+
+// LoopbackConfig returns the config the gateway dials its own grpc server with. The server is verified by
+// pinning its current certificate, and the certificate of TLSOption.LoopbackCertFile is presented as the client
+// one for mTLS.
+func (r *tlsReloader) LoopbackConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.opt.MinVersion,
+		// the certificate is verified by VerifyPeerCertificate.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			cert := r.Current().cert
+			if len(rawCerts) == 0 || len(cert.Certificate) == 0 || !bytes.Equal(rawCerts[0], cert.Certificate[0]) {
+				return errors.New("tls loopback peer certificate mismatch")
+			}
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Current().loopback, nil
+		},
+	}
+}
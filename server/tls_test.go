@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ringbrew/gsv/internal/tlstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrpcGatewayMTLS(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server.key")
+
+	ca := tlstest.NewCert(t, "ca", nil)
+	ca.Write(t, caFile, "")
+	tlstest.NewCert(t, "server", ca, x509.ExtKeyUsageServerAuth).Write(t, certFile, keyFile)
+	client := tlstest.NewCert(t, "client", ca, x509.ExtKeyUsageClientAuth)
+	loopbackCertFile := filepath.Join(dir, "loopback.pem")
+	loopbackKeyFile := filepath.Join(dir, "loopback.key")
+	tlstest.NewCert(t, "loopback", ca, x509.ExtKeyUsageClientAuth).Write(t, loopbackCertFile, loopbackKeyFile)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxyPort := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+
+	s := NewServer(GRPC, &Option{
+		ProxyPort: proxyPort,
+		CertFile:  certFile,
+		KeyFile:   keyFile,
+		TLS: TLSOption{
+			ClientCAFile:     caFile,
+			ReloadInterval:   time.Nanosecond,
+			LoopbackCertFile: loopbackCertFile,
+			LoopbackKeyFile:  loopbackKeyFile,
+		},
+		EnableGrpcGateway: true,
+		Health:            HealthOption{Enable: true},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- s.Run(ctx)
+	}()
+	select {
+	case <-s.Ready():
+	case err := <-result:
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+	get := func(certs ...tls.Certificate) (*http.Response, error) {
+		hc := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, Certificates: certs}}}
+		return hc.Get(fmt.Sprintf("https://127.0.0.1:%d%s", proxyPort, defaultLivePath))
+	}
+
+	resp, err := get(client.TLS())
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = get()
+	require.Error(t, err)
+
+	// the certificate is reloaded once the file changes.
+	time.Sleep(10 * time.Millisecond)
+	reloaded := tlstest.NewCert(t, "reloaded", ca, x509.ExtKeyUsageServerAuth)
+	reloaded.Write(t, certFile, keyFile)
+	resp, err = get(client.TLS())
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "reloaded", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	cancel()
+	require.NoError(t, <-result)
+}
+
+func TestTLSLoopback(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server.key")
+	loopbackCertFile := filepath.Join(dir, "loopback.pem")
+	loopbackKeyFile := filepath.Join(dir, "loopback.key")
+
+	ca := tlstest.NewCert(t, "ca", nil)
+	ca.Write(t, caFile, "")
+	tlstest.NewCert(t, "server", ca, x509.ExtKeyUsageServerAuth).Write(t, certFile, keyFile)
+	tlstest.NewCert(t, "loopback", ca, x509.ExtKeyUsageClientAuth).Write(t, loopbackCertFile, loopbackKeyFile)
+
+	handshake := func(opt TLSOption) error {
+		r, err := newTLSReloader(certFile, keyFile, opt)
+		require.NoError(t, err)
+
+		lis, err := tls.Listen("tcp", "127.0.0.1:0", r.ServerConfig())
+		require.NoError(t, err)
+		defer lis.Close()
+
+		result := make(chan error, 1)
+		go func() {
+			conn, err := lis.Accept()
+			if err != nil {
+				result <- err
+				return
+			}
+			defer conn.Close()
+			result <- conn.(*tls.Conn).Handshake()
+		}()
+
+		conn, err := tls.Dial("tcp", lis.Addr().String(), r.LoopbackConfig())
+		if err == nil {
+			conn.Read(make([]byte, 1))
+			conn.Close()
+		}
+		return <-result
+	}
+
+	// the server certificate is not allowed for the client authentication.
+	require.Error(t, handshake(TLSOption{ClientCAFile: caFile}))
+	require.NoError(t, handshake(TLSOption{ClientCAFile: caFile, LoopbackCertFile: loopbackCertFile, LoopbackKeyFile: loopbackKeyFile}))
+
+	_, err := newTLSReloader(certFile, keyFile, TLSOption{LoopbackCertFile: loopbackCertFile})
+	require.Error(t, err)
+}
+
+func TestTLSFileError(t *testing.T) {
+	s := NewServer(HTTP, &Option{CertFile: "not_exist.pem", KeyFile: "not_exist.key"})
+	require.Error(t, s.Run(context.Background()))
+}
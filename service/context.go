@@ -1,26 +1,18 @@
 package service
 
-import "context"
+import (
+	"context"
+	"github.com/ringbrew/gsv/internal/gsvctx"
+)
 
-type gsvCtxKey struct{}
-
-type Context interface {
-	TraceId() string
-	SpanId() string
-	ParentId() string
-	Keys() []string
-	Set(key string, value interface{})
-	Get(key string) interface{}
-	Del(key string)
-}
+type Context = gsvctx.Context
 
 // NewContext returns a new Context that carries value Context.
 func NewContext(ctx context.Context, gsvCtx Context) context.Context {
-	return context.WithValue(ctx, gsvCtxKey{}, gsvCtx)
+	return gsvctx.NewContext(ctx, gsvCtx)
 }
 
 // FromContext returns the Context value stored in ctx, if any.
 func FromContext(ctx context.Context) (Context, bool) {
-	result, ok := ctx.Value(gsvCtxKey{}).(Context)
-	return result, ok
+	return gsvctx.FromContext(ctx)
 }
@@ -51,6 +51,50 @@ type HttpRoute struct {
 	Method  string
 	Handler http.HandlerFunc
 	Meta    HttpMeta
+	// Target is the function behind Handler when it is an adapter, like the one given to NewTypedRoute.
+	// It is used to name the api in docs.
+	Target interface{}
+}
+
+type routeCtxKey struct{}
+
+// NewRouteContext returns a new Context that carries the matched route.
+func NewRouteContext(ctx context.Context, route HttpRoute) context.Context {
+	return context.WithValue(ctx, routeCtxKey{}, route)
+}
+
+// RouteFromContext returns the route matched by the request, if any.
+func RouteFromContext(ctx context.Context) (HttpRoute, bool) {
+	result, ok := ctx.Value(routeCtxKey{}).(HttpRoute)
+	return result, ok
+}
+
+type routeRecorderCtxKey struct{}
+
+// RouteRecorder is filled with the matched route by the server, it lets the middlewares running before
+// routing, like metrics, read the route once the request is served.
+type RouteRecorder struct {
+	route   HttpRoute
+	matched bool
+}
+
+// NewRouteRecorderContext returns a new Context that carries an empty RouteRecorder.
+func NewRouteRecorderContext(ctx context.Context) (context.Context, *RouteRecorder) {
+	rec := &RouteRecorder{}
+	return context.WithValue(ctx, routeRecorderCtxKey{}, rec), rec
+}
+
+// RecordRoute fills the RouteRecorder of ctx with route, if any.
+func RecordRoute(ctx context.Context, route HttpRoute) {
+	if rec, ok := ctx.Value(routeRecorderCtxKey{}).(*RouteRecorder); ok {
+		rec.route = route
+		rec.matched = true
+	}
+}
+
+// Route returns the recorded route, it is false when no route matched.
+func (r *RouteRecorder) Route() (HttpRoute, bool) {
+	return r.route, r.matched
 }
 
 func NewHttpRoute(method string, path string, handler http.HandlerFunc, meta ...HttpMeta) HttpRoute {
@@ -92,6 +136,10 @@ func (c *HttpRouteCollector) append(method string, path string, handler http.Han
 	*c = append(*c, r)
 }
 
+func (c *HttpRouteCollector) Add(route ...HttpRoute) {
+	*c = append(*c, route...)
+}
+
 func (c *HttpRouteCollector) Map(path string, handler http.HandlerFunc, meta ...HttpMeta) {
 	c.append(MethodAll, path, handler, meta...)
 }
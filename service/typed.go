@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/ringbrew/gsv/server/binding"
+	"github.com/ringbrew/gsv/server/binding/common"
+	"github.com/ringbrew/gsv/server/binding/consts"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"net/http"
+	"strings"
+)
+
+// HttpError is an error carrying the http status code to respond with.
+type HttpError struct {
+	Code    int
+	Message string
+}
+
+func (e *HttpError) Error() string {
+	return e.Message
+}
+
+func NewHttpError(code int, message string) *HttpError {
+	return &HttpError{Code: code, Message: message}
+}
+
+// Typed adapts fn into a http.HandlerFunc. The request is bound by binding.DefaultBinder().BindAndValidate,
+// binding errors are rendered by binding.RenderError, and the response is written in the ContentType of
+// the route's HttpMeta, application/json by default.
+func Typed[Req any, Resp any](fn func(ctx context.Context, req *Req) (*Resp, error)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		req := new(Req)
+		if err := binding.DefaultBinder().BindAndValidate(r, req, nil); err != nil {
+			binding.RenderError(rw, r, err)
+			return
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			writeTypedError(rw, r, err)
+			return
+		}
+
+		contentType := ContentTypeJSON
+		if route, ok := RouteFromContext(r.Context()); ok && route.Meta.ContentType != "" {
+			contentType = route.Meta.ContentType
+		}
+		writeTypedResponse(rw, contentType, resp)
+	}
+}
+
+// NewTypedRoute is NewHttpRoute for a typed handler, the Request and Response of the meta are filled by Req and Resp.
+func NewTypedRoute[Req any, Resp any](method string, path string, fn func(ctx context.Context, req *Req) (*Resp, error), meta ...HttpMeta) HttpRoute {
+	result := NewHttpRoute(method, path, Typed(fn), meta...)
+	result.Target = fn
+
+	if result.Meta.ContentType == "" {
+		result.Meta.ContentType = ContentTypeJSON
+	}
+	if result.Meta.Request == nil {
+		result.Meta.Request = new(Req)
+	}
+	if result.Meta.Response == nil {
+		result.Meta.Response = new(Resp)
+	}
+
+	return result
+}
+
+func writeTypedResponse(rw http.ResponseWriter, contentType string, resp interface{}) {
+	var data []byte
+	var err error
+
+	switch strings.ToLower(common.FilterContentType(contentType)) {
+	case consts.MIMEPROTOBUF:
+		msg, ok := resp.(proto.Message)
+		if !ok {
+			writeTypedError(rw, nil, errors.New("response does not implement 'proto.Message'"))
+			return
+		}
+		data, err = proto.Marshal(msg)
+	default:
+		data, err = json.Marshal(resp)
+	}
+
+	if err != nil {
+		writeTypedError(rw, nil, err)
+		return
+	}
+
+	rw.Header().Set(consts.HeaderContentType, contentType)
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(data)
+}
+
+func writeTypedError(rw http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := binding.AsFieldErrors(err); ok {
+		binding.RenderError(rw, r, err)
+		return
+	}
+
+	code := http.StatusInternalServerError
+	var he *HttpError
+	if errors.As(err, &he) {
+		code = he.Code
+	} else if s, ok := status.FromError(err); ok {
+		code = runtime.HTTPStatusFromCode(s.Code())
+	}
+
+	data, _ := json.Marshal(binding.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: err.Error(),
+	})
+
+	rw.Header().Set(consts.HeaderContentType, consts.MIMEApplicationProblemJSON)
+	rw.WriteHeader(code)
+	rw.Write(data)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type typedReq struct {
+	Name string `json:"name" vd:"required"`
+}
+
+type typedResp struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestNewTypedRoute(t *testing.T) {
+	route := NewTypedRoute(MethodPost, "/hello", func(ctx context.Context, req *typedReq) (*typedResp, error) {
+		if req.Name == "teapot" {
+			return nil, NewHttpError(http.StatusTeapot, "no coffee")
+		}
+		return &typedResp{Greeting: "hello " + req.Name}, nil
+	})
+
+	require.IsType(t, &typedReq{}, route.Meta.Request)
+	require.IsType(t, &typedResp{}, route.Meta.Response)
+	require.Equal(t, ContentTypeJSON, route.Meta.ContentType)
+
+	call := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/hello", bytes.NewBufferString(body))
+		r.Header.Set("Content-Type", ContentTypeJSON)
+		r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		r = r.WithContext(NewRouteContext(r.Context(), route))
+		rw := httptest.NewRecorder()
+		route.Handler(rw, r)
+		return rw
+	}
+
+	rw := call(`{"name":"gsv"}`)
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.JSONEq(t, `{"greeting":"hello gsv"}`, rw.Body.String())
+
+	rw = call(`{"name":""}`)
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+	require.Contains(t, rw.Body.String(), `"field":"name"`)
+
+	rw = call(`{"name":"teapot"}`)
+	require.Equal(t, http.StatusTeapot, rw.Code)
+}
@@ -0,0 +1,169 @@
+package tracex
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	stdout "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	"os"
+	"strings"
+)
+
+const (
+	ExporterStdOut   = "stdout"
+	ExporterZipkin   = "zipkin"
+	ExporterOTLPGrpc = "otlpgrpc"
+	ExporterOTLPHttp = "otlphttp"
+	// ExporterFile writes the spans as json lines into the file of Option.Endpoint.
+	ExporterFile = "file"
+)
+
+const compressionGzip = "gzip"
+
+func newExporter(ctx context.Context, opt Option) (trace.SpanExporter, error) {
+	if opt.Exporter != ExporterStdOut && opt.Endpoint == "" {
+		return nil, fmt.Errorf("trace exporter[%s] endpoint is required", opt.Exporter)
+	}
+
+	switch opt.Exporter {
+	case ExporterStdOut:
+		return stdout.New(stdout.WithPrettyPrint())
+	case ExporterZipkin:
+		return zipkin.New(opt.Endpoint)
+	case ExporterOTLPGrpc:
+		return newOTLPGrpcExporter(ctx, opt)
+	case ExporterOTLPHttp:
+		return newOTLPHttpExporter(ctx, opt)
+	case ExporterFile:
+		return newFileExporter(opt.Endpoint)
+	}
+	return nil, fmt.Errorf("unknown trace exporter: %s", opt.Exporter)
+}
+
+func newOTLPGrpcExporter(ctx context.Context, opt Option) (trace.SpanExporter, error) {
+	opts := make([]otlptracegrpc.Option, 0)
+
+	if strings.Contains(opt.Endpoint, "://") {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(opt.Endpoint))
+	} else {
+		opts = append(opts, otlptracegrpc.WithEndpoint(opt.Endpoint))
+	}
+
+	if len(opt.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(opt.Headers))
+	}
+
+	if opt.Compression == compressionGzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(compressionGzip))
+	}
+
+	if opt.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		tlsConfig, err := exporterTLSConfig(opt)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+func newOTLPHttpExporter(ctx context.Context, opt Option) (trace.SpanExporter, error) {
+	opts := make([]otlptracehttp.Option, 0)
+
+	if strings.Contains(opt.Endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(opt.Endpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(opt.Endpoint))
+	}
+
+	if len(opt.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(opt.Headers))
+	}
+
+	if opt.Compression == compressionGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	} else {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.NoCompression))
+	}
+
+	if opt.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	} else {
+		tlsConfig, err := exporterTLSConfig(opt)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	}
+
+	return otlptracehttp.New(ctx, opts...)
+}
+
+func exporterTLSConfig(opt Option) (*tls.Config, error) {
+	result := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if opt.CAFile != "" {
+		data, err := os.ReadFile(opt.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("trace exporter load ca error: %w", err)
+		}
+		result.RootCAs = x509.NewCertPool()
+		if !result.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("trace exporter ca file[%s] has no certificate", opt.CAFile)
+		}
+	}
+
+	if (opt.CertFile == "") != (opt.KeyFile == "") {
+		return nil, errors.New("trace exporter cert file and key file should be set together")
+	}
+	if opt.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opt.CertFile, opt.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("trace exporter load key pair error: %w", err)
+		}
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	return result, nil
+}
+
+// fileExporter writes the spans into a file, one json object per line, the file is closed on shutdown.
+type fileExporter struct {
+	*stdout.Exporter
+	f *os.File
+}
+
+func newFileExporter(path string) (trace.SpanExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("trace exporter open file error: %w", err)
+	}
+
+	exporter, err := stdout.New(stdout.WithWriter(f))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &fileExporter{Exporter: exporter, f: f}, nil
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.Exporter.Shutdown(ctx)
+	if cErr := e.f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package tracex
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	collector "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func exportSpan(t *testing.T, opt Option) {
+	exporter, err := newExporter(context.Background(), opt)
+	require.NoError(t, err)
+
+	tp := trace.NewTracerProvider(trace.WithSyncer(exporter))
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
+
+type testCollector struct {
+	collector.UnimplementedTraceServiceServer
+	headers []string
+}
+
+func (c *testCollector) Export(ctx context.Context, req *collector.ExportTraceServiceRequest) (*collector.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.headers = append(c.headers, md.Get("x-token")...)
+	return &collector.ExportTraceServiceResponse{}, nil
+}
+
+func TestOTLPGrpcExporter(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	c := &testCollector{}
+	gs := grpc.NewServer()
+	collector.RegisterTraceServiceServer(gs, c)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	exportSpan(t, Option{
+		Exporter:    ExporterOTLPGrpc,
+		Endpoint:    lis.Addr().String(),
+		Headers:     map[string]string{"x-token": "t"},
+		Compression: "gzip",
+		Insecure:    true,
+	})
+	require.Equal(t, []string{"t"}, c.headers)
+}
+
+func TestOTLPHttpExporter(t *testing.T) {
+	var paths, tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		tokens = append(tokens, r.Header.Get("x-token"))
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	exportSpan(t, Option{
+		Exporter: ExporterOTLPHttp,
+		Endpoint: srv.URL,
+		Headers:  map[string]string{"x-token": "t"},
+		Insecure: true,
+	})
+	require.Equal(t, []string{"/v1/traces"}, paths)
+	require.Equal(t, []string{"t"}, tokens)
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exportSpan(t, Option{Exporter: ExporterFile, Endpoint: path})
+	exportSpan(t, Option{Exporter: ExporterFile, Endpoint: path})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"Name":"span"`)
+}
+
+func TestExporterError(t *testing.T) {
+	_, err := newExporter(context.Background(), Option{Exporter: "jaeger", Endpoint: "localhost:14268"})
+	require.Error(t, err)
+	_, err = newExporter(context.Background(), Option{Exporter: ExporterOTLPGrpc})
+	require.Error(t, err)
+	require.Error(t, Init(Option{Exporter: ExporterZipkin}))
+}
@@ -4,17 +4,18 @@ import (
 	"context"
 	"github.com/ringbrew/gsv/logger"
 	"go.opentelemetry.io/otel"
-	stdout "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
-	"go.opentelemetry.io/otel/exporters/zipkin"
-	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"sync"
 )
 
 type Exporter string
 
-const (
-	ExporterStdOut = "stdout"
-	ExporterZipkin = "zipkin"
+var (
+	providerMu sync.Mutex
+	provider   *trace.TracerProvider
 )
 
 func Init(opts ...Option) error {
@@ -30,38 +31,104 @@ func Init(opts ...Option) error {
 		opt.Exporter = ExporterStdOut
 	}
 
+	sampler, err := newSampler(opt)
+	if err != nil {
+		return err
+	}
+
+	propagator, err := newPropagator(opt.Propagators)
+	if err != nil {
+		return err
+	}
+
 	traceOpts := []trace.TracerProviderOption{
-		// Set the sampling rate based on the parent span to 100%
-		trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(opt.Sampler))),
+		trace.WithSampler(sampler),
 	}
 
 	if opt.Exporter != "" {
 		if exporter, err := newExporter(context.Background(), opt); err != nil {
 			return err
+		} else if opt.SampleErrors {
+			traceOpts = append(traceOpts, trace.WithSpanProcessor(&errorSpanProcessor{next: trace.NewBatchSpanProcessor(exporter)}))
 		} else {
 			traceOpts = append(traceOpts, trace.WithBatcher(exporter))
 		}
 	}
 
+	debug := debugConfig{header: opt.DebugHeader, secret: opt.DebugSecret}
+	if debug.header == "" {
+		debug.header = DefaultDebugHeader
+	}
+	debugHeader.Store(debug)
+	logger.SetBaggageKeys(opt.BaggageKeys...)
+
+	res, err := newResource(opt)
+	if err != nil {
+		return err
+	}
+	traceOpts = append(traceOpts, trace.WithResource(res))
+
 	tp := trace.NewTracerProvider(traceOpts...)
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetTextMapPropagator(propagator)
 	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
 		logger.Error(logger.NewEntry().WithMessage(err.Error()))
 	}))
 
+	providerMu.Lock()
+	prev := provider
+	provider = tp
+	providerMu.Unlock()
+
+	// the provider replaced flushes its spans before it is dropped.
+	if prev != nil {
+		if err := prev.Shutdown(context.Background()); err != nil {
+			logger.Error(logger.NewEntry().WithMessage(err.Error()))
+		}
+	}
+
 	return nil
 }
 
-func newExporter(ctx context.Context, opt Option) (trace.SpanExporter, error) {
-	switch opt.Exporter {
-	case ExporterStdOut:
-		return stdout.New(stdout.WithPrettyPrint())
-	case ExporterZipkin:
-		if opt.Endpoint != "" {
-			return zipkin.New(opt.Endpoint)
-		}
+// ForceFlush exports the spans buffered by the provider of Init, until ctx is done.
+func ForceFlush(ctx context.Context) error {
+	providerMu.Lock()
+	tp := provider
+	providerMu.Unlock()
+
+	if tp == nil {
+		return nil
+	}
+	return tp.ForceFlush(ctx)
+}
+
+// Shutdown flushes and stops the provider of Init until ctx is done, it should be called before the process exits.
+func Shutdown(ctx context.Context) error {
+	providerMu.Lock()
+	tp := provider
+	provider = nil
+	providerMu.Unlock()
+
+	if tp == nil {
+		return nil
+	}
+	return tp.Shutdown(ctx)
+}
+
+// newResource merges the service attributes of opt into the default resource of the sdk.
+func newResource(opt Option) (*resource.Resource, error) {
+	attrs := make([]attribute.KeyValue, 0, 4)
+	if opt.ServiceName != "" {
+		attrs = append(attrs, semconv.ServiceNameKey.String(opt.ServiceName))
+	}
+	if opt.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(opt.ServiceVersion))
+	}
+	if opt.NodeId != "" {
+		attrs = append(attrs, semconv.ServiceInstanceIDKey.String(opt.NodeId))
+	}
+	if opt.Host != "" {
+		attrs = append(attrs, semconv.HostNameKey.String(opt.Host))
 	}
-	return nil, nil
+	return resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
 }
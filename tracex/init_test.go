@@ -0,0 +1,38 @@
+package tracex
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestInitShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	require.NoError(t, Init(Option{
+		Exporter:       ExporterFile,
+		Endpoint:       path,
+		Sampler:        1,
+		ServiceName:    "user",
+		ServiceVersion: "1.2.0",
+		NodeId:         "node-1",
+		Host:           "10.0.0.1",
+	}))
+
+	_, span := otel.Tracer("test").Start(context.Background(), "span")
+	span.End()
+
+	// the span is buffered by the batcher until flushed.
+	require.NoError(t, ForceFlush(context.Background()))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"Value":"user"`)
+	require.Contains(t, string(data), `"Value":"node-1"`)
+	require.Contains(t, string(data), `"Key":"host.name"`)
+
+	require.NoError(t, Shutdown(context.Background()))
+	require.NoError(t, Shutdown(context.Background()))
+}
@@ -0,0 +1,61 @@
+package tracex
+
+import (
+	"context"
+	"fmt"
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/service"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"strings"
+)
+
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorBaggage      = "baggage"
+	// PropagatorB3 is the single 'b3' header of zipkin.
+	PropagatorB3 = "b3"
+	// PropagatorB3Multi is the 'x-b3-*' headers of zipkin.
+	PropagatorB3Multi = "b3multi"
+	PropagatorJaeger  = "jaeger"
+)
+
+// DefaultPropagators are used when Option.Propagators is empty.
+var DefaultPropagators = []string{PropagatorTraceContext, PropagatorBaggage}
+
+// newPropagator composes the propagators by name, the context is extracted by all of them and injected in
+// every format, so the traces continue across services of different formats.
+func newPropagator(names []string) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+
+	result := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, v := range names {
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case PropagatorTraceContext:
+			result = append(result, propagation.TraceContext{})
+		case PropagatorBaggage:
+			result = append(result, propagation.Baggage{})
+		case PropagatorB3:
+			result = append(result, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			result = append(result, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			result = append(result, jaeger.Jaeger{})
+		default:
+			return nil, fmt.Errorf("unknown propagator[%s]", v)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(result...), nil
+}
+
+// CopyBaggage sets the baggage members of ctx allowed by Option.BaggageKeys into rpcCtx, so they can be read by
+// service.Context.Get and are logged with the entries of the request.
+func CopyBaggage(ctx context.Context, rpcCtx service.Context) {
+	for k, v := range logger.BaggageMembers(ctx) {
+		rpcCtx.Set(k, v)
+	}
+}
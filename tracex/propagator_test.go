@@ -0,0 +1,53 @@
+package tracex
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ringbrew/gsv/logger"
+	"github.com/ringbrew/gsv/service"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestPropagators(t *testing.T) {
+	require.NoError(t, Init(Option{Sampler: 1, Propagators: []string{"tracecontext", "baggage", "b3multi", "jaeger"}, BaggageKeys: []string{"tenant", "user"}}))
+	defer Shutdown(context.Background())
+
+	// a zipkin instrumented upstream.
+	header := http.Header{}
+	header.Set("X-B3-TraceId", "463ac35c9f6413ad48485a3953bb6124")
+	header.Set("X-B3-SpanId", "0020000000000001")
+	header.Set("X-B3-Sampled", "1")
+	header.Set("baggage", "tenant=t1,user=u1,secret=s1")
+
+	bags, sc := HttpExtract(context.Background(), propagation.HeaderCarrier(header))
+	require.Equal(t, "463ac35c9f6413ad48485a3953bb6124", sc.TraceID().String())
+	require.True(t, sc.IsSampled())
+
+	ctx := baggage.ContextWithBaggage(context.Background(), bags)
+	ctx, span := NewTraceSpanContext(ctx, "test")
+	defer span.End()
+
+	rpcCtx, ok := service.FromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "t1", rpcCtx.Get("tenant"))
+	require.Equal(t, "u1", rpcCtx.Get("user"))
+	// the keys not allowed are not copied.
+	require.Nil(t, rpcCtx.Get("secret"))
+
+	entry := logger.NewEntry(ctx)
+	require.Equal(t, "t1", entry.Extra["tenant"])
+	require.NotContains(t, entry.Extra, "secret")
+
+	// the context is injected in every format configured.
+	out := http.Header{}
+	HttpInject(ctx, propagation.HeaderCarrier(out))
+	require.NotEmpty(t, out.Get("traceparent"))
+	require.NotEmpty(t, out.Get("X-B3-TraceId"))
+	require.NotEmpty(t, out.Get("uber-trace-id"))
+
+	require.Error(t, Init(Option{Propagators: []string{"unknown"}}))
+}
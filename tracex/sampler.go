@@ -0,0 +1,303 @@
+package tracex
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultDebugHeader = "x-trace-debug"
+
+// SamplingRule decides the ratio of the traces started by the matched spans.
+type SamplingRule struct {
+	// Name is matched against the span name, which is the http path or the grpc full method, '*' matches any
+	// characters, like '/pay/*'.
+	Name string `json:"name"`
+	// Method is matched against the 'http.method' attribute when set, like 'POST'.
+	Method string `json:"method"`
+	// Ratio of the traces sampled, 1 samples all and 0 none.
+	Ratio float64 `json:"ratio"`
+}
+
+// debugConfig is the header forcing the sampling and the value it should carry.
+type debugConfig struct {
+	header string
+	secret string
+}
+
+var debugHeader atomic.Value
+
+func init() {
+	debugHeader.Store(debugConfig{header: DefaultDebugHeader})
+}
+
+// DebugHeader is the header forcing the sampling of a request, it is set by Option.DebugHeader.
+func DebugHeader() string {
+	return debugHeader.Load().(debugConfig).header
+}
+
+// debugRequested reports whether the value of the debug header is the secret, the header is ignored without one.
+func debugRequested(value string) bool {
+	c := debugHeader.Load().(debugConfig)
+	return c.secret != "" && subtle.ConstantTimeCompare([]byte(value), []byte(c.secret)) == 1
+}
+
+type forceSampleCtxKey struct{}
+
+// ContextWithForceSample returns a new Context whose spans started are sampled regardless of the rules.
+func ContextWithForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleCtxKey{}, true)
+}
+
+func forceSampleFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(forceSampleCtxKey{}).(bool)
+	return v
+}
+
+// HttpDebug marks ctx to force sampling when the request carries the debug header with Option.DebugSecret.
+func HttpDebug(ctx context.Context, hc propagation.HeaderCarrier) context.Context {
+	if debugRequested(hc.Get(DebugHeader())) {
+		return ContextWithForceSample(ctx)
+	}
+	return ctx
+}
+
+// GrpcDebug is HttpDebug for grpc metadata.
+func GrpcDebug(ctx context.Context, md *metadata.MD) context.Context {
+	if vs := md.Get(DebugHeader()); len(vs) > 0 && debugRequested(vs[0]) {
+		return ContextWithForceSample(ctx)
+	}
+	return ctx
+}
+
+type samplingRule struct {
+	name    *regexp.Regexp
+	method  string
+	sampler sdktrace.Sampler
+}
+
+func compileSamplingRule(rule SamplingRule) (samplingRule, error) {
+	if rule.Ratio < 0 || rule.Ratio > 1 {
+		return samplingRule{}, fmt.Errorf("sampling rule[%s] ratio should be in [0, 1]", rule.Name)
+	}
+
+	// span names are without the leading '/', like 'pay/create' for '/pay/create'.
+	parts := strings.Split(strings.TrimLeft(rule.Name, "/"), "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	name, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return samplingRule{}, err
+	}
+
+	return samplingRule{
+		name:    name,
+		method:  strings.ToUpper(rule.Method),
+		sampler: sdktrace.TraceIDRatioBased(rule.Ratio),
+	}, nil
+}
+
+func (r samplingRule) match(p sdktrace.SamplingParameters) bool {
+	if !r.name.MatchString(strings.TrimLeft(p.Name, "/")) {
+		return false
+	}
+	if r.method == "" {
+		return true
+	}
+	for _, v := range p.Attributes {
+		if v.Key == semconv.HTTPMethodKey {
+			return strings.ToUpper(v.Value.AsString()) == r.method
+		}
+	}
+	return false
+}
+
+// ruleSampler samples the root spans by the first matched rule, the ratio of Option.Sampler is used when none
+// matches. The sampled traces are capped by the limiter.
+type ruleSampler struct {
+	rules        []samplingRule
+	fallback     sdktrace.Sampler
+	limiter      *tokenBucket
+	sampleErrors bool
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	sampler := s.fallback
+	for _, r := range s.rules {
+		if r.match(p) {
+			sampler = r.sampler
+			break
+		}
+	}
+
+	result := sampler.ShouldSample(p)
+	if result.Decision == sdktrace.RecordAndSample && s.limiter != nil && !s.limiter.allow() {
+		result.Decision = sdktrace.Drop
+	}
+	// the span dropped is recorded still, so it can be exported by errorSpanProcessor if it fails.
+	if result.Decision == sdktrace.Drop && s.sampleErrors {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+// recordOnlySampler records the spans without sampling them.
+type recordOnlySampler struct{}
+
+func (recordOnlySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.RecordOnly,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (recordOnlySampler) Description() string {
+	return "RecordOnly"
+}
+
+// debugSampler samples the spans started with ContextWithForceSample, the others are decided by next. The traces
+// forced are capped by the limiter.
+type debugSampler struct {
+	next    sdktrace.Sampler
+	limiter *tokenBucket
+}
+
+func (s debugSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	psc := trace.SpanContextFromContext(p.ParentContext)
+	// the children of the spans sampled already follow them, without taking the tokens.
+	if forceSampleFromContext(p.ParentContext) && !psc.IsSampled() && (s.limiter == nil || s.limiter.allow()) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: psc.TraceState(),
+		}
+	}
+	return s.next.ShouldSample(p)
+}
+
+func (s debugSampler) Description() string {
+	return "DebugSampler{" + s.next.Description() + "}"
+}
+
+// newSampler builds the sampler of opt, the children follow the decision of their parents.
+func newSampler(opt Option) (sdktrace.Sampler, error) {
+	root := &ruleSampler{
+		fallback:     sdktrace.TraceIDRatioBased(opt.Sampler),
+		sampleErrors: opt.SampleErrors,
+	}
+	for _, v := range opt.Rules {
+		r, err := compileSamplingRule(v)
+		if err != nil {
+			return nil, err
+		}
+		root.rules = append(root.rules, r)
+	}
+	if opt.RateLimit > 0 {
+		root.limiter = newTokenBucket(opt.RateLimit)
+	}
+
+	var parentOpts []sdktrace.ParentBasedSamplerOption
+	if opt.SampleErrors {
+		parentOpts = append(parentOpts,
+			sdktrace.WithRemoteParentNotSampled(recordOnlySampler{}),
+			sdktrace.WithLocalParentNotSampled(recordOnlySampler{}),
+		)
+	}
+
+	result := debugSampler{next: sdktrace.ParentBased(root, parentOpts...)}
+	if opt.DebugRateLimit > 0 {
+		result.limiter = newTokenBucket(opt.DebugRateLimit)
+	}
+	return result, nil
+}
+
+// tokenBucket allows rate events per second with bursts of the same size.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// errorSpanProcessor passes the sampled spans and the failed ones recorded only to next, the failed ones are
+// exported without the rest of their traces.
+type errorSpanProcessor struct {
+	next sdktrace.SpanProcessor
+}
+
+func (p *errorSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.next.OnEnd(s)
+	} else if s.Status().Code == codes.Error {
+		p.next.OnEnd(sampledSpan{s})
+	}
+}
+
+func (p *errorSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *errorSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// sampledSpan marks a span recorded only as sampled, so the processors export it.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,124 @@
+package tracex
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSamplerRules(t *testing.T) {
+	sampler, err := newSampler(Option{
+		Sampler: 1,
+		Rules: []SamplingRule{
+			{Name: "/healthz", Ratio: 0},
+			{Name: "/pay/*", Method: "post", Ratio: 1},
+			{Name: "/pay/*", Ratio: 0},
+		},
+	})
+	require.NoError(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	tracer := tp.Tracer("test")
+
+	sampled := func(ctx context.Context, name string, method string) bool {
+		_, span := tracer.Start(ctx, name, trace.WithAttributes(semconv.HTTPMethodKey.String(method)))
+		defer span.End()
+		return span.SpanContext().IsSampled()
+	}
+
+	ctx := context.Background()
+	require.False(t, sampled(ctx, "healthz", "GET"))
+	require.True(t, sampled(ctx, "pay/order/create", "POST"))
+	require.False(t, sampled(ctx, "pay/order/create", "GET"))
+	require.True(t, sampled(ctx, "user/get", "GET"))
+
+	// the debug header forces the sampling of one request with the secret only.
+	defer debugHeader.Store(debugConfig{header: DefaultDebugHeader})
+	hc := propagation.HeaderCarrier{}
+	hc.Set(DebugHeader(), "1")
+	require.False(t, sampled(HttpDebug(ctx, hc), "healthz", "GET"))
+
+	debugHeader.Store(debugConfig{header: DefaultDebugHeader, secret: "s3cret"})
+	require.False(t, sampled(HttpDebug(ctx, hc), "healthz", "GET"))
+	hc.Set(DebugHeader(), "s3cret")
+	require.True(t, sampled(HttpDebug(ctx, hc), "healthz", "GET"))
+
+	_, err = newSampler(Option{Rules: []SamplingRule{{Name: "/", Ratio: 2}}})
+	require.Error(t, err)
+}
+
+func TestSamplerRateLimit(t *testing.T) {
+	sampler, err := newSampler(Option{Sampler: 1, RateLimit: 2})
+	require.NoError(t, err)
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler)).Tracer("test")
+
+	count := 0
+	for i := 0; i < 10; i++ {
+		ctx, span := tracer.Start(context.Background(), "root")
+		// the children follow the root and are not limited.
+		_, child := tracer.Start(ctx, "child")
+		require.Equal(t, span.SpanContext().IsSampled(), child.SpanContext().IsSampled())
+		child.End()
+		span.End()
+		if span.SpanContext().IsSampled() {
+			count++
+		}
+	}
+	require.Equal(t, 2, count)
+}
+
+func TestSamplerDebugRateLimit(t *testing.T) {
+	sampler, err := newSampler(Option{Sampler: 0, DebugRateLimit: 1})
+	require.NoError(t, err)
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler)).Tracer("test")
+
+	count := 0
+	for i := 0; i < 5; i++ {
+		ctx, span := tracer.Start(ContextWithForceSample(context.Background()), "root")
+		// the children of a trace forced do not take the tokens.
+		_, child := tracer.Start(ctx, "child")
+		require.Equal(t, span.SpanContext().IsSampled(), child.SpanContext().IsSampled())
+		child.End()
+		span.End()
+		if span.SpanContext().IsSampled() {
+			count++
+		}
+	}
+	require.Equal(t, 1, count)
+}
+
+func TestSampleErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	require.NoError(t, Init(Option{
+		Exporter:     ExporterFile,
+		Endpoint:     path,
+		Sampler:      0,
+		SampleErrors: true,
+	}))
+	defer Shutdown(context.Background())
+
+	tracer := otel.Tracer("test")
+	_, span := tracer.Start(context.Background(), "ok")
+	span.End()
+	_, span = tracer.Start(context.Background(), "failed")
+	span.SetStatus(codes.Error, "failed")
+	span.End()
+
+	require.NoError(t, ForceFlush(context.Background()))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(data), `"Name":"failed"`))
+	require.NotContains(t, string(data), `"Name":"ok"`)
+}
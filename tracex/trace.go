@@ -53,10 +53,54 @@ func NewConfig() *Config {
 }
 
 type Option struct {
-	Endpoint string  `json:"endpoint"`
-	Exporter string  `json:"exporter"`
-	Sampler  float64 `json:"sampler"`
-	Debug    bool    `json:"debug"`
+	// Endpoint is the collector address, like 'localhost:4317' or 'https://collector:4318', or the path of
+	// the file exporter.
+	Endpoint string `json:"endpoint"`
+	Exporter string `json:"exporter"`
+	// Sampler is the ratio of the traces sampled when no rule matches.
+	Sampler float64 `json:"sampler"`
+	Debug   bool    `json:"debug"`
+
+	// Rules are matched in order against the root spans, the first matched one decides the ratio.
+	Rules []SamplingRule `json:"rules"`
+	// RateLimit caps the traces sampled per second, 0 means no limit.
+	RateLimit float64 `json:"rateLimit"`
+	// SampleErrors exports the failed spans even if they are not sampled. Only the failed spans are exported, the
+	// rest of their traces is not, so the traces exported are partial, with the parents of the spans missing.
+	SampleErrors bool `json:"sampleErrors"`
+	// DebugHeader forces the sampling of the request carrying it with the value of DebugSecret,
+	// 'x-trace-debug' by default.
+	DebugHeader string `json:"debugHeader"`
+	// DebugSecret is the value of DebugHeader to force the sampling, the header is ignored when it is empty.
+	DebugSecret string `json:"debugSecret"`
+	// DebugRateLimit caps the traces forced by DebugHeader per second, 0 means no limit.
+	DebugRateLimit float64 `json:"debugRateLimit"`
+
+	// Propagators are the formats of the context across services, like 'tracecontext', 'baggage', 'b3',
+	// 'b3multi' and 'jaeger', DefaultPropagators by default.
+	Propagators []string `json:"propagators"`
+	// BaggageKeys are the keys of the baggage members copied into service.Context and the log entries,
+	// like 'tenant', none is copied by default.
+	BaggageKeys []string `json:"baggageKeys"`
+
+	// Headers are sent with every export of the otlp exporters.
+	Headers map[string]string `json:"headers"`
+	// Compression of the otlp exporters, 'gzip' or 'none'.
+	Compression string `json:"compression"`
+	// Insecure disables TLS of the otlp exporters.
+	Insecure bool `json:"insecure"`
+	// CAFile verifies the collector, the system roots are used when empty.
+	CAFile string `json:"caFile"`
+	// CertFile and KeyFile are the client certificate for mTLS.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+
+	// ServiceName, ServiceVersion, NodeId and Host are the resource attributes of the spans,
+	// server.Option.TraceOption fills them by the server.
+	ServiceName    string `json:"serviceName"`
+	ServiceVersion string `json:"serviceVersion"`
+	NodeId         string `json:"nodeId"`
+	Host           string `json:"host"`
 }
 
 type metadataSupplier struct {
@@ -198,6 +242,7 @@ func NewTraceSpanContext(ctx context.Context, spanName string) (context.Context,
 	)
 	sc := span.SpanContext()
 	rpcCtx := NewServiceContext(sc.TraceID(), sc.SpanID())
+	CopyBaggage(ctx, rpcCtx)
 	ctx = service.NewContext(ctx, rpcCtx)
 	return ctx, span
 }
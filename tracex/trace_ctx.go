@@ -71,7 +71,7 @@ func (r *rpcCtx) Set(key string, value interface{}) {
 }
 
 func (r *rpcCtx) Get(key string) interface{} {
-	r.RWMutex.RLocker()
+	r.RWMutex.RLock()
 	defer r.RWMutex.RUnlock()
 	if r.extra == nil {
 		return nil